@@ -0,0 +1,145 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// canonicalDefaults lists, for each kind of object, the properties
+// whose default value is omitted from the canonical encoding.
+var canonicalDefaults = map[string]string{
+	"node":                 `{"matrix":[1,0,0,0,0,1,0,0,0,0,1,0,0,0,0,1],"rotation":[0,0,0,1],"scale":[1,1,1],"translation":[0,0,0]}`,
+	"material":             `{"alphaCutoff":0.5,"alphaMode":"OPAQUE","doubleSided":false,"emissiveFactor":[0,0,0]}`,
+	"pbrMetallicRoughness": `{"baseColorFactor":[1,1,1,1],"metallicFactor":1,"roughnessFactor":1}`,
+	"textureInfo":          `{"texCoord":0}`,
+	"normalTexture":        `{"texCoord":0,"scale":1}`,
+	"occlusionTexture":     `{"texCoord":0,"strength":1}`,
+	"accessor":             `{"byteOffset":0,"normalized":false}`,
+	"sparseIndices":        `{"byteOffset":0}`,
+	"sparseValues":         `{"byteOffset":0}`,
+	"bufferView":           `{"byteOffset":0}`,
+	"sampler":              `{"wrapS":10497,"wrapT":10497}`,
+	"primitive":            `{"mode":4}`,
+	"animationSampler":     `{"interpolation":"LINEAR"}`,
+}
+
+// canonicalChildren maps the kind of an object and the name of one of its
+// properties to the kind of the property value, or of its elements for arrays.
+var canonicalChildren = map[string]map[string]string{
+	"document": {
+		"nodes": "node", "materials": "material", "accessors": "accessor", "bufferViews": "bufferView",
+		"samplers": "sampler", "meshes": "mesh", "animations": "animation",
+	},
+	"mesh":                 {"primitives": "primitive"},
+	"animation":            {"samplers": "animationSampler"},
+	"accessor":             {"sparse": "sparse"},
+	"sparse":               {"indices": "sparseIndices", "values": "sparseValues"},
+	"material":             {"pbrMetallicRoughness": "pbrMetallicRoughness", "normalTexture": "normalTexture", "occlusionTexture": "occlusionTexture", "emissiveTexture": "textureInfo"},
+	"pbrMetallicRoughness": {"baseColorTexture": "textureInfo", "metallicRoughnessTexture": "textureInfo"},
+}
+
+var canonicalDefaultValues map[string]map[string]interface{}
+
+func init() {
+	canonicalDefaultValues = make(map[string]map[string]interface{}, len(canonicalDefaults))
+	for kind, s := range canonicalDefaults {
+		v, err := decodeCanonical([]byte(s))
+		if err != nil {
+			panic(err)
+		}
+		canonicalDefaultValues[kind] = canonicalValue(v, "").(map[string]interface{})
+	}
+}
+
+// canonicalJSON rewrites the JSON document data so that equal documents
+// always produce the same bytes: object keys are sorted, numbers use the
+// shortest representation, default values and empty extensions are omitted.
+func canonicalJSON(data []byte, prefix, indent string) ([]byte, error) {
+	v, err := decodeCanonical(data)
+	if err != nil {
+		return nil, err
+	}
+	v = canonicalValue(v, "document")
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if len(prefix) > 0 || len(indent) > 0 {
+		enc.SetIndent(prefix, indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func decodeCanonical(data []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	err := d.Decode(&v)
+	return v, err
+}
+
+func canonicalValue(v interface{}, kind string) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		return canonicalNumber(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = canonicalValue(v[i], kind)
+		}
+		return v
+	case map[string]interface{}:
+		defaults := canonicalDefaultValues[kind]
+		for key, value := range v {
+			childKind := canonicalChildren[kind][key]
+			if key == "extensions" || key == "extras" {
+				childKind = ""
+			}
+			value = canonicalValue(value, childKind)
+			if def, ok := defaults[key]; ok && reflect.DeepEqual(value, def) {
+				delete(v, key)
+				continue
+			}
+			if key == "extensions" {
+				if ext, ok := value.(map[string]interface{}); ok && len(ext) == 0 {
+					delete(v, key)
+					continue
+				}
+			}
+			if key == "extras" && value == nil {
+				delete(v, key)
+				continue
+			}
+			v[key] = value
+		}
+		return v
+	}
+	return v
+}
+
+// canonicalNumber formats n with the shortest representation
+// that round trips, without negative zeros.
+func canonicalNumber(n json.Number) json.Number {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return json.Number(strconv.FormatInt(i, 10))
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return n
+	}
+	if f == 0 {
+		return "0"
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		return n
+	}
+	return json.Number(b)
+}
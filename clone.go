@@ -0,0 +1,131 @@
+package gltf
+
+import "reflect"
+
+// A Cloner is implemented by extension payloads and extras that need a custom deep copy,
+// for example because they hold unexported fields or resources that must be shared.
+// Clone must return a value of the same type as the receiver.
+type Cloner interface {
+	Clone() interface{}
+}
+
+// Clone returns a deep copy of doc, including the buffer and image data,
+// the extras and the extension payloads.
+//
+// Extension payloads and extras implementing Cloner are copied with Clone,
+// any other value is copied field by field, following pointers, slices and maps.
+// Unexported fields and values stored in interfaces with methods,
+// such as Buffer.Source, are shared with doc.
+// Objects referenced more than once in doc are also shared in the copy.
+func (doc *Document) Clone() *Document {
+	if doc == nil {
+		return nil
+	}
+	c := &deepCopier{ptrs: make(map[copiedPointer]reflect.Value)}
+	return c.copy(reflect.ValueOf(doc)).Interface().(*Document)
+}
+
+type copiedPointer struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type deepCopier struct {
+	ptrs map[copiedPointer]reflect.Value
+}
+
+func (c *deepCopier) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || v.NumMethod() > 0 {
+			return v
+		}
+		elem := c.copy(v.Elem())
+		out := reflect.New(v.Type()).Elem()
+		out.Set(elem)
+		return out
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		if cv, ok := cloneValue(v); ok {
+			return cv
+		}
+		key := copiedPointer{v.Pointer(), v.Type()}
+		if out, ok := c.ptrs[key]; ok {
+			return out
+		}
+		out := reflect.New(v.Type().Elem())
+		c.ptrs[key] = out
+		out.Elem().Set(c.copy(v.Elem()))
+		return out
+	case reflect.Struct:
+		if cv, ok := cloneValue(v); ok {
+			return cv
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out.Field(i).Set(c.copy(v.Field(i)))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		if isPlainKind(v.Type().Elem().Kind()) {
+			reflect.Copy(out, v)
+			return out
+		}
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+		return out
+	}
+	return v
+}
+
+// cloneValue calls Clone if v implements Cloner and returns a value of the type of v.
+func cloneValue(v reflect.Value) (reflect.Value, bool) {
+	if !v.CanInterface() {
+		return v, false
+	}
+	cl, ok := v.Interface().(Cloner)
+	if !ok {
+		return v, false
+	}
+	out := reflect.ValueOf(cl.Clone())
+	if !out.IsValid() || out.Type() != v.Type() {
+		return v, false
+	}
+	return out, true
+}
+
+func isPlainKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		return true
+	}
+	return false
+}
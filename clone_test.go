@@ -0,0 +1,74 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type testClonerExt struct {
+	Values []int
+	cloned bool
+}
+
+func (e *testClonerExt) Clone() interface{} {
+	return &testClonerExt{Values: append([]int(nil), e.Values...), cloned: true}
+}
+
+func TestDocument_Clone(t *testing.T) {
+	src := bytes.NewReader([]byte{1, 2})
+	shared := &Accessor{Name: "shared", Min: []float32{1}}
+	doc := &Document{
+		Asset:      Asset{Version: "2.0", Extras: map[string]interface{}{"a": []interface{}{1.0}}},
+		Extensions: Extensions{"raw": json.RawMessage(`{"a":1}`), "cloner": &testClonerExt{Values: []int{1}}},
+		Accessors:  []*Accessor{shared, shared},
+		Buffers:    []*Buffer{{ByteLength: 2, Data: []byte{1, 2}}, {ByteLength: 2, Source: src}},
+		Images:     []*Image{{URI: "a.png", Data: []byte{3}}},
+		Nodes: []*Node{{
+			Children:   []uint32{1},
+			Extensions: Extensions{"value": testExtension{1}, "pointer": &testExtension{2}},
+		}},
+		Scene: Index(0),
+	}
+	c := doc.Clone()
+	if diff := deep.Equal(c, doc); diff != nil {
+		t.Fatalf("Clone() = %v", diff)
+	}
+
+	c.Asset.Extras.(map[string]interface{})["a"].([]interface{})[0] = 2.0
+	c.Extensions["raw"].(json.RawMessage)[1] = 'b'
+	c.Accessors[0].Min[0] = 2
+	c.Buffers[0].Data[0] = 9
+	c.Images[0].Data[0] = 9
+	c.Nodes[0].Children[0] = 9
+	c.Nodes[0].Extensions["pointer"].(*testExtension).Value = 9
+	*c.Scene = 9
+	if diff := deep.Equal(doc, &Document{
+		Asset:      Asset{Version: "2.0", Extras: map[string]interface{}{"a": []interface{}{1.0}}},
+		Extensions: Extensions{"raw": json.RawMessage(`{"a":1}`), "cloner": &testClonerExt{Values: []int{1}}},
+		Accessors:  []*Accessor{shared, shared},
+		Buffers:    []*Buffer{{ByteLength: 2, Data: []byte{1, 2}}, {ByteLength: 2, Source: src}},
+		Images:     []*Image{{URI: "a.png", Data: []byte{3}}},
+		Nodes: []*Node{{
+			Children:   []uint32{1},
+			Extensions: Extensions{"value": testExtension{1}, "pointer": &testExtension{2}},
+		}},
+		Scene: Index(0),
+	}); diff != nil {
+		t.Errorf("Clone() modified the original: %v", diff)
+	}
+	if c.Accessors[0] != c.Accessors[1] || c.Accessors[0] == shared {
+		t.Error("Clone() expected shared objects to stay shared in the copy")
+	}
+	if c.Buffers[1].Source != src {
+		t.Error("Clone() expected buffer source to be shared")
+	}
+	if !c.Extensions["cloner"].(*testClonerExt).cloned {
+		t.Error("Clone() expected Cloner to be used")
+	}
+	if (*Document)(nil).Clone() != nil {
+		t.Error("Clone() of nil document should be nil")
+	}
+}
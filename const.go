@@ -444,7 +444,6 @@ type glbHeader struct {
 }
 
 const (
+	dataURIPrefix            = "data:"
 	mimetypeApplicationOctet = "data:application/octet-stream;base64"
-	mimetypeImagePNG         = "data:image/png;base64"
-	mimetypeImageJPG         = "data:image/jpeg;base64"
 )
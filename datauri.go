@@ -0,0 +1,77 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Image media types supported by the core specification and its extensions.
+const (
+	MimeTypePNG  = "image/png"
+	MimeTypeJPEG = "image/jpeg"
+	MimeTypeWebP = "image/webp"
+	MimeTypeKTX2 = "image/ktx2"
+	MimeTypeAVIF = "image/avif"
+	MimeTypeGIF  = "image/gif"
+)
+
+// parseDataURI decodes a data URI as defined by RFC 2397,
+// returning its media type, without parameters, and its content.
+// Both base64 and percent-encoded contents are supported.
+func parseDataURI(uri string) (string, []byte, error) {
+	if !strings.HasPrefix(uri, dataURIPrefix) {
+		return "", nil, errors.New("gltf: not a data uri")
+	}
+	header, content, ok := strings.Cut(uri[len(dataURIPrefix):], ",")
+	if !ok {
+		return "", nil, errors.New("gltf: malformed data uri")
+	}
+	params := strings.Split(header, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+	if mediaType == "" {
+		mediaType = "text/plain"
+	}
+	isBase64 := len(params) > 1 && strings.EqualFold(params[len(params)-1], "base64")
+	if !isBase64 {
+		data, err := url.PathUnescape(content)
+		if err != nil {
+			return "", nil, err
+		}
+		return mediaType, []byte(data), nil
+	}
+	if strings.Contains(content, "%") {
+		var err error
+		if content, err = url.PathUnescape(content); err != nil {
+			return "", nil, err
+		}
+	}
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", nil, err
+	}
+	return mediaType, data, nil
+}
+
+// DetectImageMimeType returns the media type of the image encoded in data,
+// based on its signature. It recognizes PNG, JPEG, WebP, KTX2, AVIF and GIF
+// images and returns an empty string for any other content.
+func DetectImageMimeType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return MimeTypePNG
+	case bytes.HasPrefix(data, []byte{0xff, 0xd8, 0xff}):
+		return MimeTypeJPEG
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return MimeTypeWebP
+	case bytes.HasPrefix(data, []byte("\xabKTX 20\xbb\r\n\x1a\n")):
+		return MimeTypeKTX2
+	case len(data) >= 12 && string(data[4:8]) == "ftyp" && (string(data[8:12]) == "avif" || string(data[8:12]) == "avis"):
+		return MimeTypeAVIF
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return MimeTypeGIF
+	}
+	return ""
+}
@@ -9,9 +9,12 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -34,9 +37,56 @@ func Open(name string) (*Document, error) {
 //
 // Only buffers with relative URIs will be read from Fsys.
 // Fsys is called to read external resources.
+// Resolver, if not nil, is called to read resources with absolute URIs
+// and relative ones when Fsys is nil. Without Resolver, Decode fails
+// on the resources with absolute URIs, such as http:// or file:// ones;
+// use HTTPResolver or FileResolver to read them.
+//
+// If LoadImages is true the images referenced by an URI are read into Image.Data.
+//
+// Limits bounds the resources consumed when decoding untrusted input.
+//
+// If Strict is true Decode fails with an *ExtensionError when the document
+// requires an extension that has not been registered with RegisterExtension
+// or when it uses an extension not listed in extensionsUsed.
 type Decoder struct {
-	Fsys fs.FS
-	r    *bufio.Reader
+	Fsys       fs.FS
+	Resolver   ResourceResolver
+	Strict     bool
+	LoadImages bool
+	Limits     DecoderLimits
+	r          *bufio.Reader
+	ra         io.ReaderAt
+	glb        *glbHeader
+	files      int64
+	loaded     int64
+}
+
+// DecoderLimits defines the maximum amount of resources a Decoder can consume.
+// Zero values mean no limit.
+type DecoderLimits struct {
+	MaxJSONBytes     int64 // Size of the JSON document or of the GLB JSON chunk.
+	MaxBufferBytes   int64 // Sum of the byteLength of all the buffers and of the size of the loaded images.
+	MaxArrayLength   int64 // Number of elements of any array or object in the JSON document.
+	MaxExternalFiles int64 // Number of external buffers and images read.
+}
+
+// LimitError is returned by a Decoder when the document exceeds one of its DecoderLimits.
+type LimitError struct {
+	Limit string // Name of the exceeded DecoderLimits field.
+	Value int64
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("gltf: %s exceeded: %d > %d", e.Limit, e.Value, e.Max)
+}
+
+func checkLimit(limit string, value, max int64) error {
+	if max > 0 && value > max {
+		return &LimitError{Limit: limit, Value: value, Max: max}
+	}
+	return nil
 }
 
 // NewDecoder returns a new decoder that reads from r.
@@ -54,13 +104,37 @@ func NewDecoderFS(r io.Reader, fsys fs.FS) *Decoder {
 	}
 }
 
+// NewDecoderAt returns a new decoder that reads from r without
+// loading the GLB BIN chunk into memory.
+//
+// The buffer stored in the BIN chunk is decoded with a nil Data
+// and a Source that reads from r on demand, so r must remain
+// open while the document is in use. Call Buffer.Load to
+// bring the whole buffer into memory.
+func NewDecoderAt(r io.ReaderAt, fsys fs.FS) *Decoder {
+	return &Decoder{
+		Fsys: fsys,
+		r:    bufio.NewReader(io.NewSectionReader(r, 0, math.MaxInt64)),
+		ra:   r,
+	}
+}
+
 // Decode reads the next JSON-encoded value from its
 // input and stores it in the value pointed to by doc.
 func (d *Decoder) Decode(doc *Document) error {
-	isBinary, err := d.decodeDocument(doc)
+	d.glb, d.files, d.loaded = nil, 0, 0
+	header, err := d.decodeDocument(doc)
 	if err != nil {
 		return err
 	}
+	if err := d.checkLimits(doc); err != nil {
+		return err
+	}
+	if d.Strict {
+		if err := checkExtensions(doc); err != nil {
+			return err
+		}
+	}
 
 	for _, b := range doc.Buffers {
 		if !b.IsEmbeddedResource() {
@@ -78,9 +152,14 @@ func (d *Decoder) Decode(doc *Document) error {
 	}
 
 	var externalBufferIndex = 0
-	if isBinary && len(doc.Buffers) > 0 && doc.Buffers[0].URI == "" {
+	if header != nil && len(doc.Buffers) > 0 && doc.Buffers[0].URI == "" {
 		externalBufferIndex = 1
-		if err := d.decodeBinaryBuffer(doc.Buffers[0]); err != nil {
+		if d.ra != nil {
+			err = d.decodeBinaryBufferAt(doc.Buffers[0], int64(binary.Size(header))+int64(header.JSONHeader.Length))
+		} else {
+			err = d.decodeBinaryBuffer(doc.Buffers[0])
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -89,28 +168,177 @@ func (d *Decoder) Decode(doc *Document) error {
 			return err
 		}
 	}
+	if d.LoadImages {
+		for _, im := range doc.Images {
+			if err := d.decodeImage(im); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExtensionError is returned by a strict Decoder when the document
+// extensions can't be honored.
+type ExtensionError struct {
+	Unsupported []string // Extensions listed in extensionsRequired without a registered implementation.
+	Undeclared  []string // Extensions present in the document but missing from extensionsUsed.
+}
+
+func (e *ExtensionError) Error() string {
+	var msgs []string
+	if len(e.Unsupported) > 0 {
+		msgs = append(msgs, "unsupported required extensions "+strings.Join(e.Unsupported, ", "))
+	}
+	if len(e.Undeclared) > 0 {
+		msgs = append(msgs, "extensions not declared in extensionsUsed "+strings.Join(e.Undeclared, ", "))
+	}
+	return "gltf: " + strings.Join(msgs, "; ")
+}
+
+func checkExtensions(doc *Document) error {
+	var extErr ExtensionError
+	for _, name := range doc.ExtensionsRequired {
+		if _, ok := queryExtension(name); !ok {
+			extErr.Unsupported = append(extErr.Unsupported, name)
+		}
+	}
+	for _, name := range extensionNames(doc) {
+		if !doc.HasExtensionUsed(name) {
+			extErr.Undeclared = append(extErr.Undeclared, name)
+		}
+	}
+	if len(extErr.Unsupported) > 0 || len(extErr.Undeclared) > 0 {
+		return &extErr
+	}
 	return nil
 }
 
-func (d *Decoder) decodeDocument(doc *Document) (bool, error) {
+// extensionNames returns the sorted names of all the extensions
+// found in the Extensions maps of doc and its children.
+func extensionNames(doc *Document) []string {
+	seen := make(map[string]struct{})
+	var visit func(v reflect.Value)
+	visit = func(v reflect.Value) {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if ext, ok := v.Field(i).Interface().(Extensions); ok {
+					for name := range ext {
+						seen[name] = struct{}{}
+					}
+					continue
+				}
+				visit(v.Field(i))
+			}
+		case reflect.Slice:
+			if k := v.Type().Elem().Kind(); k != reflect.Ptr && k != reflect.Struct {
+				return
+			}
+			for i := 0; i < v.Len(); i++ {
+				visit(v.Index(i))
+			}
+		}
+	}
+	visit(reflect.ValueOf(doc))
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *Decoder) decodeDocument(doc *Document) (*glbHeader, error) {
 	glbHeader, err := d.readGLBHeader()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	var (
-		jd       *json.Decoder
-		isBinary bool
+		jd *json.Decoder
+		lr *io.LimitedReader
 	)
 	if glbHeader != nil {
+		if err := checkLimit("MaxJSONBytes", int64(glbHeader.JSONHeader.Length), d.Limits.MaxJSONBytes); err != nil {
+			return nil, err
+		}
+		d.glb = glbHeader
 		jd = json.NewDecoder(&io.LimitedReader{R: d.r, N: int64(glbHeader.JSONHeader.Length)})
-		isBinary = true
+	} else if d.Limits.MaxJSONBytes > 0 {
+		lr = &io.LimitedReader{R: d.r, N: d.Limits.MaxJSONBytes + 1}
+		jd = json.NewDecoder(lr)
 	} else {
 		jd = json.NewDecoder(d.r)
-		isBinary = false
 	}
 
-	err = jd.Decode(doc)
-	return isBinary, err
+	if d.Limits.MaxArrayLength > 0 {
+		// The arrays are checked before decoding so that they are never allocated.
+		var raw json.RawMessage
+		if err = jd.Decode(&raw); err == nil {
+			if err = checkArrayLength(raw, d.Limits.MaxArrayLength); err == nil {
+				err = json.Unmarshal(raw, doc)
+			}
+		}
+	} else {
+		err = jd.Decode(doc)
+	}
+	if lr != nil && lr.N <= 0 {
+		return nil, checkLimit("MaxJSONBytes", d.Limits.MaxJSONBytes+1, d.Limits.MaxJSONBytes)
+	}
+	return glbHeader, err
+}
+
+func (d *Decoder) checkLimits(doc *Document) error {
+	for _, b := range doc.Buffers {
+		d.loaded += int64(b.ByteLength)
+	}
+	return checkLimit("MaxBufferBytes", d.loaded, d.Limits.MaxBufferBytes)
+}
+
+// checkArrayLength returns an error if any array or object
+// of the JSON document data has more than max elements.
+func checkArrayLength(data []byte, max int64) error {
+	type level struct {
+		n      int64
+		object bool
+	}
+	var stack []level
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		delim, isDelim := tok.(json.Delim)
+		if isDelim && (delim == ']' || delim == '}') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			top.n++
+			n := top.n
+			if top.object {
+				// Keys and values are returned as separate tokens.
+				n = (n + 1) / 2
+			}
+			if err := checkLimit("MaxArrayLength", n, max); err != nil {
+				return err
+			}
+		}
+		if isDelim {
+			stack = append(stack, level{object: delim == '{'})
+		}
+	}
 }
 
 func (d *Decoder) readGLBHeader() (*glbHeader, error) {
@@ -129,9 +357,34 @@ func (d *Decoder) readGLBHeader() (*glbHeader, error) {
 }
 
 func (d *Decoder) validateGLBHeader(header *glbHeader) error {
-	if header.JSONHeader.Type != glbChunkJSON || (header.JSONHeader.Length+uint32(binary.Size(header))) > header.Length {
+	if header.Version != 2 {
+		return fmt.Errorf("gltf: Unsupported GLB version %d", header.Version)
+	}
+	if header.JSONHeader.Type != glbChunkJSON || uint64(header.JSONHeader.Length)+uint64(binary.Size(header)) > uint64(header.Length) {
 		return errors.New("gltf: Invalid GLB JSON header")
 	}
+	if header.JSONHeader.Length%4 != 0 {
+		return errors.New("gltf: Invalid GLB JSON chunk padding")
+	}
+	return nil
+}
+
+// validateBINHeader checks header against buffer and,
+// when decoding a GLB, against the lengths declared in the GLB header.
+func (d *Decoder) validateBINHeader(header *chunkHeader, buffer *Buffer) error {
+	if header.Type != glbChunkBIN || header.Length < uint32(buffer.ByteLength) {
+		return errors.New("gltf: Invalid GLB BIN header")
+	}
+	if d.glb == nil {
+		return nil
+	}
+	if header.Length%4 != 0 || header.Length-buffer.ByteLength > 3 {
+		return errors.New("gltf: Invalid GLB BIN chunk padding")
+	}
+	size := uint64(binary.Size(d.glb)) + uint64(d.glb.JSONHeader.Length) + uint64(binary.Size(header)) + uint64(header.Length)
+	if size > uint64(d.glb.Length) {
+		return errors.New("gltf: GLB chunks exceed the declared length")
+	}
 	return nil
 }
 
@@ -147,24 +400,24 @@ func (d *Decoder) decodeBuffer(buffer *Buffer, doc *Document, bufferIndex int) e
 	if err := d.validateBuffer(buffer); err != nil {
 		return err
 	}
-	// 检查是否有bufferView引用此缓冲区
-	isReferenced := false
-	for _, bv := range doc.BufferViews {
-		if bv.Buffer == uint32(bufferIndex) {
-			isReferenced = true
-			break
+	if buffer.URI == "" {
+		// 检查是否有bufferView引用此缓冲区
+		if doc != nil {
+			for _, bv := range doc.BufferViews {
+				if bv.Buffer == uint32(bufferIndex) {
+					return nil
+				}
+			}
 		}
-	}
-	if buffer.URI == "" && !isReferenced {
 		return errors.New("gltf: buffer without URI")
 	}
 	var err error
 	if buffer.IsEmbeddedResource() {
 		buffer.Data, err = buffer.MarshalData()
-	} else if !isReferenced {
-		err = validateBufferURI(buffer.URI)
-		if err == nil && d.Fsys != nil {
-			buffer.Data, err = fs.ReadFile(d.Fsys, buffer.URI)
+	} else {
+		err = validateURI("buffer", buffer.URI)
+		if err == nil {
+			buffer.Data, err = d.readResource(buffer.URI, int64(buffer.ByteLength))
 			if len(buffer.Data) > int(buffer.ByteLength) {
 				buffer.Data = buffer.Data[:buffer.ByteLength:buffer.ByteLength]
 			}
@@ -176,6 +429,70 @@ func (d *Decoder) decodeBuffer(buffer *Buffer, doc *Document, bufferIndex int) e
 	return err
 }
 
+func (d *Decoder) decodeImage(im *Image) error {
+	if im.URI == "" || im.BufferView != nil {
+		return nil
+	}
+	var err error
+	if im.IsEmbeddedResource() {
+		im.Data, err = im.MarshalData()
+	} else {
+		err = validateURI("image", im.URI)
+		if err == nil {
+			// Read one byte more than allowed to detect oversized images.
+			n := int64(-1)
+			if d.Limits.MaxBufferBytes > 0 {
+				n = d.Limits.MaxBufferBytes - d.loaded + 1
+			}
+			im.Data, err = d.readResource(im.URI, n)
+		}
+	}
+	if err == nil {
+		d.loaded += int64(len(im.Data))
+		err = checkLimit("MaxBufferBytes", d.loaded, d.Limits.MaxBufferBytes)
+	}
+	if err != nil {
+		im.Data = nil
+	}
+	return err
+}
+
+// readResource reads up to n bytes, or all if n is negative,
+// of the external resource referenced by uri.
+// It returns nil if uri is relative and there is no way to read it.
+func (d *Decoder) readResource(uri string, n int64) ([]byte, error) {
+	absolute := isAbsoluteURI(uri)
+	fromFsys := !absolute && d.Fsys != nil
+	if !fromFsys && d.Resolver == nil {
+		if absolute {
+			return nil, fmt.Errorf("gltf: no resolver to read '%s'", uri)
+		}
+		return nil, nil
+	}
+	d.files++
+	if err := checkLimit("MaxExternalFiles", d.files, d.Limits.MaxExternalFiles); err != nil {
+		return nil, err
+	}
+	var (
+		rc  io.ReadCloser
+		err error
+	)
+	if fromFsys {
+		rc, err = d.Fsys.Open(uri)
+	} else {
+		rc, err = d.Resolver.ReadResource(uri)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var r io.Reader = rc
+	if n >= 0 {
+		r = io.LimitReader(rc, n)
+	}
+	return io.ReadAll(r)
+}
+
 func (d *Decoder) decodeBinaryBuffer(buffer *Buffer) error {
 	if err := d.validateBuffer(buffer); err != nil {
 		return err
@@ -184,14 +501,53 @@ func (d *Decoder) decodeBinaryBuffer(buffer *Buffer) error {
 	if err != nil {
 		return err
 	}
-	if header.Type != glbChunkBIN || header.Length < uint32(buffer.ByteLength) {
-		return errors.New("gltf: Invalid GLB BIN header")
+	if err := d.validateBINHeader(header, buffer); err != nil {
+		return err
+	}
+	buffer.Data, err = readFull(d.r, int64(buffer.ByteLength))
+	if err != nil {
+		buffer.Data = nil
 	}
-	buffer.Data = make([]byte, buffer.ByteLength)
-	_, err = io.ReadFull(d.r, buffer.Data)
 	return err
 }
 
+// readFull reads exactly n bytes from r.
+// The returned slice grows as data arrives, so a forged
+// length does not allocate more memory than the input size.
+func readFull(r io.Reader, n int64) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(int(min(n, 1<<20)))
+	_, err := io.CopyN(&buf, r, n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return buf.Bytes(), err
+}
+
+// decodeBinaryBufferAt sets the Source of buffer to the content of
+// the BIN chunk found at offset, without reading it.
+func (d *Decoder) decodeBinaryBufferAt(buffer *Buffer, offset int64) error {
+	if err := d.validateBuffer(buffer); err != nil {
+		return err
+	}
+	var header chunkHeader
+	size := int64(binary.Size(header))
+	if err := binary.Read(io.NewSectionReader(d.ra, offset, size), binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if err := d.validateBINHeader(&header, buffer); err != nil {
+		return err
+	}
+	// Fail early if the chunk is truncated.
+	var last [1]byte
+	if _, err := d.ra.ReadAt(last[:], offset+size+int64(buffer.ByteLength)-1); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	buffer.Data = nil
+	buffer.Source = io.NewSectionReader(d.ra, offset+size, int64(buffer.ByteLength))
+	return nil
+}
+
 func (d *Decoder) validateBuffer(buffer *Buffer) error {
 	if buffer.ByteLength == 0 {
 		return errors.New("gltf: Invalid buffer.byteLength value = 0")
@@ -199,16 +555,21 @@ func (d *Decoder) validateBuffer(buffer *Buffer) error {
 	return nil
 }
 
-func validateBufferURI(uri string) error {
-	if u, err := url.Parse(uri); err == nil && u.Scheme != "" {
+func validateURI(property, uri string) error {
+	if isAbsoluteURI(uri) {
 		return nil
 	}
 	if !filepath.IsLocal(uri) {
-		return fmt.Errorf("gltf: Invalid buffer.uri value '%s'", uri)
+		return fmt.Errorf("gltf: Invalid %s.uri value '%s'", property, uri)
 	}
 	return nil
 }
 
+func isAbsoluteURI(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.Scheme != ""
+}
+
 func sanitizeURI(uri string) (string, bool) {
 	uri = strings.Replace(uri, "\\", "/", -1)
 	uri = strings.Replace(uri, "/./", "/", -1)
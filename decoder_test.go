@@ -2,8 +2,11 @@ package gltf
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 	"testing/fstest"
@@ -184,7 +187,7 @@ func TestDecoder_decodeBuffer(t *testing.T) {
 		{"byteLength_0", &Decoder{}, args{&Buffer{ByteLength: 0, URI: "a.bin"}}, nil, true},
 		{"noURI", &Decoder{}, args{&Buffer{ByteLength: 1, URI: ""}}, nil, true},
 		{"invalidURI", &Decoder{}, args{&Buffer{ByteLength: 1, URI: "../a.bin"}}, nil, true},
-		{"noSchemeErr", NewDecoder(nil), args{&Buffer{ByteLength: 3, URI: "ftp://a.bin"}}, nil, false},
+		{"noResolver", NewDecoder(nil), args{&Buffer{ByteLength: 3, URI: "ftp://a.bin"}}, nil, true},
 		{"base", NewDecoderFS(nil, fstest.MapFS{"a.bin": &fstest.MapFile{Data: []byte("abcdfg")}}), args{&Buffer{ByteLength: 6, URI: "a.bin"}}, []byte("abcdfg"), false},
 	}
 	for _, tt := range tests {
@@ -260,6 +263,140 @@ func TestDecoder_Decode(t *testing.T) {
 	}
 }
 
+func TestDecoder_Decode_at(t *testing.T) {
+	name := "testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb"
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var doc Document
+	if err := NewDecoderAt(f, nil).Decode(&doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	want, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := doc.Buffers[0]
+	if buf.Data != nil || buf.Source == nil {
+		t.Fatalf("Decoder.Decode() eagerly loaded the BIN chunk")
+	}
+	bv := doc.BufferViews[1]
+	got, err := buf.ReadRange(bv.ByteOffset, bv.ByteLength)
+	if err != nil {
+		t.Fatalf("Buffer.ReadRange() error = %v", err)
+	}
+	if !bytes.Equal(got, want.Buffers[0].Data[bv.ByteOffset:bv.ByteOffset+bv.ByteLength]) {
+		t.Errorf("Buffer.ReadRange() = %v", got)
+	}
+	if _, err := buf.ReadRange(buf.ByteLength, 1); err == nil {
+		t.Error("Buffer.ReadRange() expected error reading out of bounds")
+	}
+
+	var gotGLB, wantGLB bytes.Buffer
+	if err := NewEncoder(&gotGLB).Encode(&doc); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	if err := NewEncoder(&wantGLB).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gotGLB.Bytes(), wantGLB.Bytes()) {
+		t.Error("Encoder.Encode() lazy document differs from the loaded one")
+	}
+
+	if err := buf.Load(); err != nil {
+		t.Fatalf("Buffer.Load() error = %v", err)
+	}
+	if !bytes.Equal(buf.Data, want.Buffers[0].Data) {
+		t.Error("Buffer.Load() data mismatch")
+	}
+}
+
+func TestDecoder_Decode_atTruncated(t *testing.T) {
+	data := readFile("testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb")
+	var doc Document
+	if err := NewDecoderAt(bytes.NewReader(data[:len(data)-8]), nil).Decode(&doc); err == nil {
+		t.Error("Decoder.Decode() expected error on truncated BIN chunk")
+	}
+}
+
+func TestDecoder_Decode_limits(t *testing.T) {
+	glb := readFile("testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb")
+	withLength := func(length uint32) []byte {
+		data := append([]byte(nil), glb...)
+		binary.LittleEndian.PutUint32(data[8:], length)
+		return data
+	}
+	fsys := fstest.MapFS{"a.bin": &fstest.MapFile{Data: []byte("abcdfg")}, "b.bin": &fstest.MapFile{Data: []byte("abcdfg")}}
+	twoBuffers := `{"buffers": [{"byteLength": 6, "uri": "a.bin"}, {"byteLength": 6, "uri": "b.bin"}]}`
+	tests := []struct {
+		name   string
+		data   []byte
+		limits DecoderLimits
+		limit  string
+	}{
+		{"none", glb, DecoderLimits{}, ""},
+		{"glbJSON", glb, DecoderLimits{MaxJSONBytes: 1000}, "MaxJSONBytes"},
+		{"json", []byte(twoBuffers), DecoderLimits{MaxJSONBytes: 10}, "MaxJSONBytes"},
+		{"jsonFit", []byte(twoBuffers), DecoderLimits{MaxJSONBytes: int64(len(twoBuffers))}, ""},
+		{"bufferBytes", glb, DecoderLimits{MaxBufferBytes: 1000}, "MaxBufferBytes"},
+		{"arrayLength", glb, DecoderLimits{MaxArrayLength: 4}, "MaxArrayLength"},
+		{"externalFiles", []byte(twoBuffers), DecoderLimits{MaxExternalFiles: 1}, "MaxExternalFiles"},
+		{"externalFilesFit", []byte(twoBuffers), DecoderLimits{MaxExternalFiles: 2}, ""},
+		{"hugeByteLength", []byte(`{"buffers": [{"byteLength": 4294967295, "uri": "a.bin"}]}`), DecoderLimits{MaxBufferBytes: 1 << 20}, "MaxBufferBytes"},
+		{"extensionArray", []byte(`{"extensions": {"EXT_a": [1, 2, 3, 4, 5]}}`), DecoderLimits{MaxArrayLength: 4}, "MaxArrayLength"},
+		{"objectFit", []byte(`{"asset": {"version": "2.0"}, "scenes": [{}, {}]}`), DecoderLimits{MaxArrayLength: 2}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoderFS(bytes.NewReader(tt.data), fsys)
+			d.Limits = tt.limits
+			err := d.Decode(new(Document))
+			var lerr *LimitError
+			if tt.limit == "" {
+				if err != nil {
+					t.Errorf("Decoder.Decode() error = %v", err)
+				}
+			} else if !errors.As(err, &lerr) || lerr.Limit != tt.limit {
+				t.Errorf("Decoder.Decode() error = %v, want %s LimitError", err, tt.limit)
+			}
+		})
+	}
+
+	images := `{"buffers": [{"byteLength": 6, "uri": "a.bin"}], "images": [{"uri": "a.png"}]}`
+	fsys["a.png"] = &fstest.MapFile{Data: make([]byte, 10)}
+	for max, limit := range map[int64]string{15: "MaxBufferBytes", 16: ""} {
+		d := NewDecoderFS(bytes.NewReader([]byte(images)), fsys)
+		d.LoadImages = true
+		d.Limits.MaxBufferBytes = max
+		err := d.Decode(new(Document))
+		var lerr *LimitError
+		if (limit == "" && err != nil) || (limit != "" && (!errors.As(err, &lerr) || lerr.Limit != limit)) {
+			t.Errorf("Decoder.Decode() images with %d bytes error = %v, want %q", max, err, limit)
+		}
+	}
+
+	headerTests := []struct {
+		name string
+		data []byte
+	}{
+		{"shortLength", withLength(uint32(len(glb) - 4))},
+		{"version", append(append(append([]byte(nil), glb[:4]...), 1, 0, 0, 0), glb[8:]...)},
+		{"truncated", glb[:len(glb)-4]},
+	}
+	for _, tt := range headerTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewDecoder(bytes.NewReader(tt.data)).Decode(new(Document)); err == nil {
+				t.Error("Decoder.Decode() expected error")
+			}
+			if err := NewDecoderAt(bytes.NewReader(tt.data), nil).Decode(new(Document)); err == nil {
+				t.Error("Decoder.Decode() at expected error")
+			}
+		})
+	}
+}
+
 func TestSampler_Decode(t *testing.T) {
 
 	tests := []struct {
@@ -288,3 +425,41 @@ func TestSampler_Decode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoder_Decode_strict(t *testing.T) {
+	RegisterExtension("FAKE_supported", func(data []byte) (interface{}, error) { return string(data), nil })
+	tests := []struct {
+		name    string
+		json    string
+		want    *ExtensionError
+		wantErr bool
+	}{
+		{"valid", `{"extensionsUsed":["FAKE_supported"],"extensionsRequired":["FAKE_supported"],"extensions":{"FAKE_supported":{}}}`, nil, false},
+		{"unsupported", `{"extensionsUsed":["FAKE_missing"],"extensionsRequired":["FAKE_missing"]}`, &ExtensionError{Unsupported: []string{"FAKE_missing"}}, true},
+		{"undeclared", `{"nodes":[{"extensions":{"FAKE_other":{}}}],"materials":[{"pbrMetallicRoughness":{"baseColorTexture":{"index":0,"extensions":{"FAKE_supported":{}}}}}]}`,
+			&ExtensionError{Undeclared: []string{"FAKE_other", "FAKE_supported"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoder(bytes.NewBufferString(tt.json))
+			d.Strict = true
+			err := d.Decode(new(Document))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decoder.Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				return
+			}
+			extErr, ok := err.(*ExtensionError)
+			if !ok {
+				t.Fatalf("Decoder.Decode() error type = %T, want *ExtensionError", err)
+			}
+			if !reflect.DeepEqual(extErr, tt.want) {
+				t.Errorf("Decoder.Decode() error = %v, want %v", extErr, tt.want)
+			}
+		})
+	}
+	if err := NewDecoder(bytes.NewBufferString(`{"extensionsRequired":["FAKE_missing"]}`)).Decode(new(Document)); err != nil {
+		t.Errorf("Decoder.Decode() non strict error = %v", err)
+	}
+}
@@ -55,13 +55,21 @@ func save(doc *Document, name string, asBinary bool) error {
 
 // An Encoder writes a glTF to an output stream.
 //
-// Only buffers with relative URIs will be written to Fsys.
+// Only buffers and images with relative URIs will be written to Fsys.
+// ResourceWriter, if not nil, is called to write resources with absolute URIs
+// and relative ones when Fsys is nil.
+//
+// If Canonical is true encoding the same document always produces the same bytes:
+// object keys are sorted, numbers use their shortest representation and
+// properties with default values or empty extensions are omitted.
 type Encoder struct {
-	AsBinary bool
-	Fsys     CreateFS
-	w        io.Writer
-	indent   string
-	prefix   string
+	AsBinary       bool
+	Canonical      bool
+	Fsys           CreateFS
+	ResourceWriter ResourceWriter
+	w              io.Writer
+	indent         string
+	prefix         string
 }
 
 // NewEncoder returns a new encoder that writes to w as a normal glTF file.
@@ -114,7 +122,15 @@ func (e *Encoder) Encode(doc *Document) error {
 		if len(buf.Data) == 0 || buf.URI == "" || buf.IsEmbeddedResource() {
 			continue
 		}
-		if err = e.encodeBuffer(buf); err != nil {
+		if err = e.encodeResource("buffer", buf.URI, buf.Data); err != nil {
+			return err
+		}
+	}
+	for _, im := range doc.Images {
+		if len(im.Data) == 0 || im.URI == "" || im.BufferView != nil || im.IsEmbeddedResource() {
+			continue
+		}
+		if err = e.encodeResource("image", im.URI, im.Data); err != nil {
 			return err
 		}
 	}
@@ -122,14 +138,17 @@ func (e *Encoder) Encode(doc *Document) error {
 	return err
 }
 
-func (e *Encoder) encodeBuffer(buffer *Buffer) error {
-	if err := validateBufferURI(buffer.URI); err != nil {
+func (e *Encoder) encodeResource(property, uri string, data []byte) error {
+	if err := validateURI(property, uri); err != nil {
 		return err
 	}
-	if e.Fsys == nil {
+	if isAbsoluteURI(uri) || e.Fsys == nil {
+		if e.ResourceWriter != nil {
+			return e.ResourceWriter.WriteResource(uri, data)
+		}
 		return nil
 	}
-	uri, ok := sanitizeURI(buffer.URI)
+	uri, ok := sanitizeURI(uri)
 	if !ok {
 		return nil
 	}
@@ -137,7 +156,7 @@ func (e *Encoder) encodeBuffer(buffer *Buffer) error {
 	if err != nil {
 		return err
 	}
-	_, err = w.Write(buffer.Data)
+	_, err = w.Write(data)
 	if err1 := w.Close(); err == nil {
 		err = err1
 	}
@@ -186,7 +205,14 @@ func (e *Encoder) encodeBinary(doc *Document) (bool, error) {
 		}
 		binHeader := chunkHeader{Length: binPaddedLength, Type: glbChunkBIN}
 		binary.Write(e.w, binary.LittleEndian, &binHeader)
-		e.w.Write(binBuffer.Data)
+		if binBuffer.Data == nil && binBuffer.Source != nil {
+			_, err = io.Copy(e.w, io.NewSectionReader(binBuffer.Source, 0, int64(binBuffer.ByteLength)))
+		} else {
+			_, err = e.w.Write(binBuffer.Data)
+		}
+		if err != nil {
+			return hasBinChunk, err
+		}
 		_, err = e.w.Write(binPadding)
 	}
 
@@ -211,6 +237,20 @@ func (e *Encoder) marshalJSONDoc(doc *Document) ([]byte, error) {
 			tmp.CustomBuffers[i] = buf
 			continue
 		}
+		if buf.URI == "" && buf.Data == nil && buf.Source != nil {
+			// Lazily decoded buffers have to be loaded to be embedded.
+			data, err := buf.ReadRange(0, buf.ByteLength)
+			if err != nil {
+				return nil, err
+			}
+			buf = &Buffer{
+				Extensions: buf.Extensions,
+				Extras:     buf.Extras,
+				Name:       buf.Name,
+				ByteLength: buf.ByteLength,
+				Data:       data,
+			}
+		}
 		if len(buf.Data) > 0 && buf.URI == "" && !buf.IsEmbeddedResource() {
 			tmpBuf := &Buffer{
 				Extensions: buf.Extensions,
@@ -225,6 +265,13 @@ func (e *Encoder) marshalJSONDoc(doc *Document) ([]byte, error) {
 			tmp.CustomBuffers[i] = buf
 		}
 	}
+	if e.Canonical {
+		data, err := json.Marshal(tmp)
+		if err != nil {
+			return nil, err
+		}
+		return canonicalJSON(data, e.prefix, e.indent)
+	}
 	if len(e.prefix) > 0 || len(e.indent) > 0 {
 		return json.MarshalIndent(tmp, e.prefix, e.indent)
 	}
@@ -234,12 +281,16 @@ func (e *Encoder) marshalJSONDoc(doc *Document) ([]byte, error) {
 // UnmarshalJSON unmarshal the asset with the correct default values.
 func (as *Asset) UnmarshalJSON(data []byte) error {
 	type alias Asset
-	tmp := alias(Asset{
+	tmp := struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}{alias: alias(Asset{
 		Version: "2.0",
-	})
+	})}
 	err := json.Unmarshal(data, &tmp)
 	if err == nil {
-		*as = Asset(tmp)
+		*as = Asset(tmp.alias)
+		as.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeAsset)
 	}
 	return err
 }
@@ -262,14 +313,18 @@ func (as *Asset) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshal the node with the correct default values.
 func (n *Node) UnmarshalJSON(data []byte) error {
 	type alias Node
-	tmp := alias(Node{
+	tmp := struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}{alias: alias(Node{
 		Matrix:   DefaultMatrix,
 		Rotation: DefaultRotation,
 		Scale:    DefaultScale,
-	})
+	})}
 	err := json.Unmarshal(data, &tmp)
 	if err == nil {
-		*n = Node(tmp)
+		*n = Node(tmp.alias)
+		n.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeNode)
 	}
 	return err
 }
@@ -327,10 +382,14 @@ func (c *Camera) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshal the material with the correct default values.
 func (m *Material) UnmarshalJSON(data []byte) error {
 	type alias Material
-	tmp := alias(Material{AlphaCutoff: Float(0.5)})
+	tmp := struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}{alias: alias(Material{AlphaCutoff: Float(0.5)})}
 	err := json.Unmarshal(data, &tmp)
 	if err == nil {
-		*m = Material(tmp)
+		*m = Material(tmp.alias)
+		m.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeMaterial)
 	}
 	return err
 }
@@ -357,10 +416,14 @@ func (m *Material) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshal the texture info with the correct default values.
 func (n *NormalTexture) UnmarshalJSON(data []byte) error {
 	type alias NormalTexture
-	tmp := alias(NormalTexture{Scale: Float(1)})
+	tmp := struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}{alias: alias(NormalTexture{Scale: Float(1)})}
 	err := json.Unmarshal(data, &tmp)
 	if err == nil {
-		*n = NormalTexture(tmp)
+		*n = NormalTexture(tmp.alias)
+		n.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeTextureInfo)
 	}
 	return err
 }
@@ -383,10 +446,14 @@ func (n *NormalTexture) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshal the texture info with the correct default values.
 func (o *OcclusionTexture) UnmarshalJSON(data []byte) error {
 	type alias OcclusionTexture
-	tmp := alias(OcclusionTexture{Strength: Float(1)})
+	tmp := struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}{alias: alias(OcclusionTexture{Strength: Float(1)})}
 	err := json.Unmarshal(data, &tmp)
 	if err == nil {
-		*o = OcclusionTexture(tmp)
+		*o = OcclusionTexture(tmp.alias)
+		o.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeTextureInfo)
 	}
 	return err
 }
@@ -437,8 +504,234 @@ func (p *PBRMetallicRoughness) MarshalJSON() ([]byte, error) {
 	return json.Marshal(tmp)
 }
 
+// UnmarshalJSON unmarshal the document with its extensions decoded for ScopeDocument.
+// The fields that are not present in data are left unchanged.
+func (doc *Document) UnmarshalJSON(data []byte) error {
+	type alias Document
+	tmp := struct {
+		*alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}{alias: (*alias)(doc)}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil && tmp.Extensions != nil {
+		doc.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeDocument)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the accessor with its extensions decoded for ScopeAccessor.
+func (a *Accessor) UnmarshalJSON(data []byte) error {
+	type alias Accessor
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*a = Accessor(tmp.alias)
+		a.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeAccessor)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the animation with its extensions decoded for ScopeAnimation.
+func (a *Animation) UnmarshalJSON(data []byte) error {
+	type alias Animation
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*a = Animation(tmp.alias)
+		a.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeAnimation)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the buffer with its extensions decoded for ScopeBuffer.
+func (b *Buffer) UnmarshalJSON(data []byte) error {
+	type alias Buffer
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*b = Buffer(tmp.alias)
+		b.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeBuffer)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the buffer view with its extensions decoded for ScopeBufferView.
+func (b *BufferView) UnmarshalJSON(data []byte) error {
+	type alias BufferView
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*b = BufferView(tmp.alias)
+		b.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeBufferView)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the camera with its extensions decoded for ScopeCamera.
+func (c *Camera) UnmarshalJSON(data []byte) error {
+	type alias Camera
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*c = Camera(tmp.alias)
+		c.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeCamera)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the image with its extensions decoded for ScopeImage.
+func (im *Image) UnmarshalJSON(data []byte) error {
+	type alias Image
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*im = Image(tmp.alias)
+		im.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeImage)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the mesh with its extensions decoded for ScopeMesh.
+func (m *Mesh) UnmarshalJSON(data []byte) error {
+	type alias Mesh
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*m = Mesh(tmp.alias)
+		m.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeMesh)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the primitive with its extensions decoded for ScopePrimitive.
+func (p *Primitive) UnmarshalJSON(data []byte) error {
+	type alias Primitive
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*p = Primitive(tmp.alias)
+		p.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopePrimitive)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the sampler with its extensions decoded for ScopeSampler.
+func (s *Sampler) UnmarshalJSON(data []byte) error {
+	type alias Sampler
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*s = Sampler(tmp.alias)
+		s.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeSampler)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the scene with its extensions decoded for ScopeScene.
+func (s *Scene) UnmarshalJSON(data []byte) error {
+	type alias Scene
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*s = Scene(tmp.alias)
+		s.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeScene)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the skin with its extensions decoded for ScopeSkin.
+func (s *Skin) UnmarshalJSON(data []byte) error {
+	type alias Skin
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*s = Skin(tmp.alias)
+		s.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeSkin)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the texture with its extensions decoded for ScopeTexture.
+func (t *Texture) UnmarshalJSON(data []byte) error {
+	type alias Texture
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*t = Texture(tmp.alias)
+		t.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeTexture)
+	}
+	return err
+}
+
+// UnmarshalJSON unmarshal the texture info with its extensions decoded for ScopeTextureInfo.
+func (t *TextureInfo) UnmarshalJSON(data []byte) error {
+	type alias TextureInfo
+	var tmp struct {
+		alias
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+	err := json.Unmarshal(data, &tmp)
+	if err == nil {
+		*t = TextureInfo(tmp.alias)
+		t.Extensions, err = unmarshalExtensions(tmp.Extensions, ScopeTextureInfo)
+	}
+	return err
+}
+
 // UnmarshalJSON unmarshal the extensions with the supported extensions initialized.
+// The factories registered for ScopeAny are used, as the owner of the extensions is unknown.
 func (ext *Extensions) UnmarshalJSON(data []byte) error {
+	return ext.unmarshalScoped(data, ScopeAny)
+}
+
+// unmarshalExtensions decodes the extensions of an object of the given scope.
+// It returns nil if data is empty.
+func unmarshalExtensions(data json.RawMessage, scope ExtensionScope) (Extensions, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var ext Extensions
+	err := ext.unmarshalScoped(data, scope)
+	return ext, err
+}
+
+func (ext *Extensions) unmarshalScoped(data []byte, scope ExtensionScope) error {
 	if len(*ext) == 0 {
 		*ext = make(Extensions)
 	}
@@ -446,7 +739,7 @@ func (ext *Extensions) UnmarshalJSON(data []byte) error {
 	err := json.Unmarshal(data, &raw)
 	if err == nil {
 		for key, value := range raw {
-			if extFactory, ok := queryExtension(key); ok {
+			if extFactory, ok := queryScopedExtension(key, scope); ok {
 				n, cerr := extFactory(value)
 				if cerr != nil {
 					(*ext)[key] = value
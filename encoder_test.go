@@ -287,6 +287,8 @@ func TestImage_MarshalData(t *testing.T) {
 		{"empty", &Image{URI: "data:image/png;base64,"}, []byte{}, false},
 		{"empty", &Image{URI: "data:image/jpeg;base64,"}, []byte{}, false},
 		{"test", &Image{URI: "data:image/png;base64,TEST"}, []byte{76, 68, 147}, false},
+		{"webp", &Image{URI: "data:image/webp;base64,TEST"}, []byte{76, 68, 147}, false},
+		{"ktx2", &Image{URI: "data:image/ktx2;base64,TEST"}, []byte{76, 68, 147}, false},
 		{"complex", &Image{URI: "data:image/png;base64,YW55IGNhcm5hbCBwbGVhcw=="}, []byte{97, 110, 121, 32, 99, 97, 114, 110, 97, 108, 32, 112, 108, 101, 97, 115}, false},
 	}
 	for _, tt := range tests {
@@ -713,3 +715,138 @@ func TestExtensions_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestExtensions_UnmarshalJSON_scoped(t *testing.T) {
+	RegisterExtension("fake_scoped_ext", func(data []byte) (interface{}, error) {
+		return "any", nil
+	})
+	RegisterScopedExtension("fake_scoped_ext", ScopeNode, func(data []byte) (interface{}, error) {
+		return "node", nil
+	})
+	RegisterScopedExtension("fake_scoped_ext", ScopePrimitive, func(data []byte) (interface{}, error) {
+		return "primitive", nil
+	})
+	data := []byte(`{
+		"asset": {"version": "2.0"},
+		"extensions": {"fake_scoped_ext": {}},
+		"nodes": [{"extensions": {"fake_scoped_ext": {}}}],
+		"meshes": [{"primitives": [{"attributes": {}, "extensions": {"fake_scoped_ext": {}}}]}],
+		"materials": [{"normalTexture": {"index": 0, "extensions": {"fake_scoped_ext": {}}}}]
+	}`)
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		ext  Extensions
+		want string
+	}{
+		{"document", doc.Extensions, "any"},
+		{"node", doc.Nodes[0].Extensions, "node"},
+		{"primitive", doc.Meshes[0].Primitives[0].Extensions, "primitive"},
+		{"textureInfo", doc.Materials[0].NormalTexture.Extensions, "any"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ext["fake_scoped_ext"]; got != tt.want {
+				t.Errorf("Extensions[fake_scoped_ext] = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !reflect.DeepEqual(doc.Nodes[0].Matrix, DefaultMatrix) {
+		t.Errorf("Node.Matrix = %v, want %v", doc.Nodes[0].Matrix, DefaultMatrix)
+	}
+}
+
+func TestDocument_UnmarshalJSON(t *testing.T) {
+	doc := Document{
+		Samplers:   []*Sampler{{WrapS: WrapClampToEdge}},
+		Extensions: Extensions{"a": "kept"},
+	}
+	if err := json.Unmarshal([]byte(`{"asset": {"version": "2.0"}}`), &doc); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := Document{
+		Asset:      Asset{Version: "2.0"},
+		Samplers:   []*Sampler{{WrapS: WrapClampToEdge}},
+		Extensions: Extensions{"a": "kept"},
+	}
+	if diff := deep.Equal(doc, want); diff != nil {
+		t.Errorf("json.Unmarshal() = %v", diff)
+	}
+}
+
+func Test_queryExtension(t *testing.T) {
+	for _, scope := range []ExtensionScope{ScopeSkin, ScopeNode, ScopeMaterial, ScopePrimitive} {
+		scope := scope
+		RegisterScopedExtension("fake_query_ext", scope, func(data []byte) (interface{}, error) {
+			return scope, nil
+		})
+	}
+	for i := 0; i < 20; i++ {
+		f, ok := queryExtension("fake_query_ext")
+		if !ok {
+			t.Fatal("queryExtension() expected a factory")
+		}
+		if got, _ := f(nil); got != ScopeMaterial {
+			t.Fatalf("queryExtension() factory of scope %v, want %v", got, ScopeMaterial)
+		}
+	}
+	if _, ok := queryExtension("fake_missing_ext"); ok {
+		t.Error("queryExtension() expected no factory")
+	}
+}
+
+func TestEncoder_Encode_canonical(t *testing.T) {
+	doc := &Document{
+		Asset:          Asset{Version: "2.0"},
+		ExtensionsUsed: []string{"B_ext", "A_ext"},
+		Extensions: Extensions{
+			"B_ext": json.RawMessage(`{ "z": 1.0, "a": -0.0 }`),
+			"A_ext": map[string]interface{}{"v": 1e-7},
+		},
+		Nodes: []*Node{{
+			Name:       "n",
+			Matrix:     DefaultMatrix,
+			Rotation:   DefaultRotation,
+			Scale:      [3]float32{1, 2, 1},
+			Extensions: Extensions{},
+		}},
+		Materials: []*Material{{
+			AlphaCutoff:          Float(0.5),
+			PBRMetallicRoughness: &PBRMetallicRoughness{MetallicFactor: Float(1), RoughnessFactor: Float(0.25)},
+			NormalTexture:        &NormalTexture{Index: Index(0), Scale: Float(1)},
+		}},
+		Samplers: []*Sampler{{WrapS: WrapRepeat, WrapT: WrapMirroredRepeat}},
+	}
+	encode := func(doc *Document) string {
+		var buf bytes.Buffer
+		e := NewEncoder(&buf)
+		e.AsBinary = false
+		e.Canonical = true
+		if err := e.Encode(doc); err != nil {
+			t.Fatalf("Encoder.Encode() error = %v", err)
+		}
+		return buf.String()
+	}
+	want := `{"asset":{"version":"2.0"},"extensions":{"A_ext":{"v":1e-7},"B_ext":{"a":0,"z":1}},` +
+		`"extensionsUsed":["B_ext","A_ext"],"materials":[{"normalTexture":{"index":0},"pbrMetallicRoughness":{"roughnessFactor":0.25}}],` +
+		`"nodes":[{"name":"n","scale":[1,2,1]}],"samplers":[{"wrapT":33648}]}`
+	got := encode(doc)
+	if got != want {
+		t.Errorf("Encoder.Encode() = %s, want %s", got, want)
+	}
+	for i := 0; i < 3; i++ {
+		if again := encode(doc); again != got {
+			t.Fatalf("Encoder.Encode() is not deterministic: %s != %s", again, got)
+		}
+	}
+	decoded := new(Document)
+	if err := NewDecoder(strings.NewReader(got)).Decode(decoded); err != nil {
+		t.Fatal(err)
+	}
+	if again := encode(decoded); again != got {
+		t.Errorf("Encoder.Encode() round trip = %s, want %s", again, got)
+	}
+}
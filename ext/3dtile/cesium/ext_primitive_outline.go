@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/flywave/gltf"
+	"github.com/flywave/gltf/modeler"
 )
 
 const (
@@ -23,6 +24,12 @@ type CesiumPrimitiveOutline struct {
 	Extras     json.RawMessage            `json:"extras,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the accessor index rewritten by remap.
+func (e CesiumPrimitiveOutline) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	e.Indices = gltf.RemapIndex(remap, gltf.PropertyAccessors, e.Indices)
+	return e
+}
+
 // UnmarshalCesiumPrimitiveOutline unmarshals the CESIUM_primitive_outline extension data
 func UnmarshalCesiumPrimitiveOutline(data []byte) (interface{}, error) {
 	var ext CesiumPrimitiveOutline
@@ -33,6 +40,9 @@ func UnmarshalCesiumPrimitiveOutline(data []byte) (interface{}, error) {
 }
 
 // SetCesiumOutline sets the Cesium outline vertex indices for a primitive
+//
+// Deprecated: SetCesiumOutline doesn't write the indices. Use WriteCesiumOutline,
+// which also records the extension in the document's extensionsUsed.
 func SetCesiumOutline(primitive *gltf.Primitive, indices []uint32, accessorName string) error {
 	if primitive.Extensions == nil {
 		primitive.Extensions = make(gltf.Extensions)
@@ -56,29 +66,21 @@ func SetCesiumOutline(primitive *gltf.Primitive, indices []uint32, accessorName
 	return nil
 }
 
-// GetCesiumOutline gets the Cesium outline extension from a primitive
-func GetCesiumOutline(primitive *gltf.Primitive) (*CesiumPrimitiveOutline, error) {
-	if primitive.Extensions == nil {
-		return nil, fmt.Errorf("no extensions found")
-	}
-
-	extData, exists := primitive.Extensions[ExtensionName]
-	if !exists {
-		return nil, fmt.Errorf("%s extension not found", ExtensionName)
-	}
-
-	// Type assertion
-	extDataBytes, ok := extData.([]byte)
-	if !ok {
-		return nil, fmt.Errorf("extension data is not in expected format ([]byte)")
-	}
-
-	var ext CesiumPrimitiveOutline
-	if err := json.Unmarshal(extDataBytes, &ext); err != nil {
-		return nil, fmt.Errorf("error unmarshaling CESIUM_primitive_outline extension: %w", err)
+// WriteCesiumOutline writes the outline vertex indices to a new UNSIGNED_INT accessor
+// named accessorName and sets the Cesium outline extension of primitive to reference it.
+func WriteCesiumOutline(doc *gltf.Document, primitive *gltf.Primitive, indices []uint32, accessorName string) error {
+	if len(indices) == 0 {
+		return fmt.Errorf("CESIUM_primitive_outline requires at least one index")
 	}
+	index := modeler.WriteIndices(doc, indices)
+	doc.Accessors[index].Name = accessorName
+	gltf.SetExtension(doc, &primitive.Extensions, ExtensionName, CesiumPrimitiveOutline{Indices: gltf.Index(index)})
+	return nil
+}
 
-	return &ext, nil
+// GetCesiumOutline gets the Cesium outline extension from a primitive
+func GetCesiumOutline(primitive *gltf.Primitive) (*CesiumPrimitiveOutline, error) {
+	return gltf.GetExtension[*CesiumPrimitiveOutline](primitive.Extensions, ExtensionName)
 }
 
 // ValidateCesiumOutlineIndices validates that all indices are within the range of the mesh primitive indices
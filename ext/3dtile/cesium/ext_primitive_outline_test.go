@@ -8,24 +8,35 @@ import (
 
 func TestCesiumPrimitiveOutline(t *testing.T) {
 	// Test creating and setting Cesium outline extension
+	doc := gltf.NewDocument()
 	primitive := &gltf.Primitive{
 		Extensions: make(gltf.Extensions),
 	}
 
-	// Test SetCesiumOutline
+	// Test WriteCesiumOutline
 	indices := []uint32{0, 1, 2, 3, 4, 5}
-	err := SetCesiumOutline(primitive, indices, "test outline")
+	err := WriteCesiumOutline(doc, primitive, indices, "test outline")
 	if err != nil {
-		t.Errorf("SetCesiumOutline failed: %v", err)
+		t.Errorf("WriteCesiumOutline failed: %v", err)
+	}
+	if !doc.HasExtensionUsed(ExtensionName) {
+		t.Error("WriteCesiumOutline should add the extension to extensionsUsed")
 	}
 
 	// Test GetCesiumOutline
 	ext, err := GetCesiumOutline(primitive)
 	if err != nil {
-		t.Errorf("GetCesiumOutline failed: %v", err)
+		t.Fatalf("GetCesiumOutline failed: %v", err)
+	}
+	if ext == nil || ext.Indices == nil {
+		t.Fatal("GetCesiumOutline returned no indices")
 	}
-	if ext == nil {
-		t.Error("GetCesiumOutline returned nil extension")
+	acr := doc.Accessors[*ext.Indices]
+	if err := ValidateAccessor(acr); err != nil || acr.Count != uint32(len(indices)) || acr.Name != "test outline" {
+		t.Errorf("WriteCesiumOutline accessor = %+v, %v", acr, err)
+	}
+	if err := WriteCesiumOutline(doc, primitive, nil, ""); err == nil {
+		t.Error("WriteCesiumOutline should fail without indices")
 	}
 
 	// Test ValidateCesiumOutlineIndices
@@ -78,3 +89,22 @@ func TestValidateAccessor(t *testing.T) {
 		t.Error("ValidateAccessor should fail for normalized accessor")
 	}
 }
+
+func TestCesiumPrimitiveOutline_Prune(t *testing.T) {
+	doc := &gltf.Document{
+		Scenes: []*gltf.Scene{{Nodes: []uint32{0}}},
+		Nodes:  []*gltf.Node{{Mesh: gltf.Index(0)}},
+		Meshes: []*gltf.Mesh{{Primitives: []*gltf.Primitive{{
+			Attributes: gltf.Attribute{gltf.POSITION: 1},
+			Extensions: gltf.Extensions{ExtensionName: CesiumPrimitiveOutline{Indices: gltf.Index(2)}},
+		}}}},
+		Accessors: []*gltf.Accessor{{Name: "unused"}, {Name: "position"}, {Name: "outline"}},
+	}
+	if _, err := gltf.Prune(doc); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	ext := doc.Meshes[0].Primitives[0].Extensions[ExtensionName].(CesiumPrimitiveOutline)
+	if len(doc.Accessors) != 2 || *ext.Indices != 1 || doc.Accessors[1].Name != "outline" {
+		t.Errorf("Prune() = %v, indices %d", doc.Accessors, *ext.Indices)
+	}
+}
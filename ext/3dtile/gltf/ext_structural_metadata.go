@@ -3,6 +3,7 @@ package gltf
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/flywave/gltf"
 )
@@ -10,8 +11,16 @@ import (
 // ExtStructuralMetadata represents the root EXT_structural_metadata object
 const ExtensionName = "EXT_structural_metadata"
 
+// Names of the arrays defined by the extension in gltf.IndexRemapFunc.
+const (
+	PropertyTablesProperty     = ExtensionName + ".propertyTables"
+	PropertyTexturesProperty   = ExtensionName + ".propertyTextures"
+	PropertyAttributesProperty = ExtensionName + ".propertyAttributes"
+)
+
 func init() {
 	gltf.RegisterExtension(ExtensionName, UnmarshalExtStructuralMetadata)
+	gltf.RegisterScopedExtension(ExtensionName, gltf.ScopeDocument, UnmarshalExtStructuralMetadata)
 }
 
 type ExtStructuralMetadata struct {
@@ -22,6 +31,84 @@ type ExtStructuralMetadata struct {
 	PropertyAttributes []PropertyAttribute `json:"propertyAttributes,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the buffer view indices
+// of the property tables rewritten by remap.
+func (e ExtStructuralMetadata) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	if e.PropertyTables != nil {
+		tables := make([]PropertyTable, len(e.PropertyTables))
+		for i, table := range e.PropertyTables {
+			props := make(map[string]PropertyTableProperty, len(table.Properties))
+			for name, prop := range table.Properties {
+				prop.Values = remap(gltf.PropertyBufferViews, prop.Values)
+				prop.ArrayOffsets = gltf.RemapIndex(remap, gltf.PropertyBufferViews, prop.ArrayOffsets)
+				prop.StringOffsets = gltf.RemapIndex(remap, gltf.PropertyBufferViews, prop.StringOffsets)
+				props[name] = prop
+			}
+			table.Properties = props
+			tables[i] = table
+		}
+		e.PropertyTables = tables
+	}
+	return e
+}
+
+// MergeExtension combines the schemas of e and other, which must be an ExtStructuralMetadata,
+// and appends the property tables, textures and attributes of other to the ones of e.
+// Classes and enums defined by both schemas must be equal.
+func (e ExtStructuralMetadata) MergeExtension(other interface{}) (interface{}, map[string]uint32, error) {
+	o, ok := other.(ExtStructuralMetadata)
+	if !ok {
+		return nil, nil, fmt.Errorf("gltf: cannot merge %s with %T", ExtensionName, other)
+	}
+	if e.SchemaURI != nil && o.SchemaURI != nil && *e.SchemaURI != *o.SchemaURI {
+		return nil, nil, fmt.Errorf("gltf: cannot merge %s with different schema uris", ExtensionName)
+	}
+	merged := e
+	if merged.SchemaURI == nil {
+		merged.SchemaURI = o.SchemaURI
+	}
+	if o.Schema != nil {
+		if e.Schema == nil {
+			merged.Schema = o.Schema
+		} else {
+			schema := *e.Schema
+			var err error
+			if schema.Classes, err = mergeDefinitions(e.Schema.Classes, o.Schema.Classes); err != nil {
+				return nil, nil, err
+			}
+			if schema.Enums, err = mergeDefinitions(e.Schema.Enums, o.Schema.Enums); err != nil {
+				return nil, nil, err
+			}
+			merged.Schema = &schema
+		}
+	}
+	merged.PropertyTables = append(e.PropertyTables[:len(e.PropertyTables):len(e.PropertyTables)], o.PropertyTables...)
+	merged.PropertyTextures = append(e.PropertyTextures[:len(e.PropertyTextures):len(e.PropertyTextures)], o.PropertyTextures...)
+	merged.PropertyAttributes = append(e.PropertyAttributes[:len(e.PropertyAttributes):len(e.PropertyAttributes)], o.PropertyAttributes...)
+	return merged, map[string]uint32{
+		PropertyTablesProperty:     uint32(len(e.PropertyTables)),
+		PropertyTexturesProperty:   uint32(len(e.PropertyTextures)),
+		PropertyAttributesProperty: uint32(len(e.PropertyAttributes)),
+	}, nil
+}
+
+func mergeDefinitions[T any](a, b map[string]T) (map[string]T, error) {
+	if len(b) == 0 {
+		return a, nil
+	}
+	merged := make(map[string]T, len(a)+len(b))
+	for id, v := range a {
+		merged[id] = v
+	}
+	for id, v := range b {
+		if cur, ok := merged[id]; ok && !reflect.DeepEqual(cur, v) {
+			return nil, fmt.Errorf("gltf: cannot merge %s, %s is defined differently", ExtensionName, id)
+		}
+		merged[id] = v
+	}
+	return merged, nil
+}
+
 // UnmarshalExtStructuralMetadata unmarshals the EXT_structural_metadata extension data
 func UnmarshalExtStructuralMetadata(data []byte) (interface{}, error) {
 	var ext ExtStructuralMetadata
@@ -2,6 +2,8 @@ package gltf
 
 import (
 	"testing"
+
+	"github.com/flywave/gltf"
 )
 
 func TestUnmarshalExtStructuralMetadata(t *testing.T) {
@@ -77,3 +79,40 @@ func TestUnmarshalExtStructuralMetadataInvalid(t *testing.T) {
 		t.Error("UnmarshalExtStructuralMetadata should return nil for invalid data")
 	}
 }
+
+func TestExtStructuralMetadata_MergeExtension(t *testing.T) {
+	schema := func(classes ...string) *Schema {
+		s := &Schema{ID: "schema", Classes: map[string]Class{}}
+		for _, c := range classes {
+			s.Classes[c] = Class{}
+		}
+		return s
+	}
+	dst := &gltf.Document{Extensions: gltf.Extensions{ExtensionName: ExtStructuralMetadata{
+		Schema:         schema("a"),
+		PropertyTables: []PropertyTable{{Class: "a", Count: 1, Properties: map[string]PropertyTableProperty{"p": {Values: 0}}}},
+	}}, BufferViews: []*gltf.BufferView{{}}}
+	src := &gltf.Document{Extensions: gltf.Extensions{ExtensionName: &ExtStructuralMetadata{
+		Schema:         schema("a", "b"),
+		PropertyTables: []PropertyTable{{Class: "b", Count: 1, Properties: map[string]PropertyTableProperty{"p": {Values: 0, StringOffsets: gltf.Index(1)}}}},
+	}}, BufferViews: []*gltf.BufferView{{}, {}}}
+	if err := gltf.Merge(dst, src); err != nil {
+		t.Fatalf("gltf.Merge() error = %v", err)
+	}
+	got := dst.Extensions[ExtensionName].(ExtStructuralMetadata)
+	if len(got.Schema.Classes) != 2 || len(got.PropertyTables) != 2 {
+		t.Fatalf("gltf.Merge() = %+v", got)
+	}
+	if p := got.PropertyTables[1].Properties["p"]; p.Values != 1 || *p.StringOffsets != 2 {
+		t.Errorf("gltf.Merge() property = %+v", p)
+	}
+	if p := got.PropertyTables[0].Properties["p"]; p.Values != 0 {
+		t.Errorf("gltf.Merge() modified dst property = %+v", p)
+	}
+
+	name := "other"
+	conflict := ExtStructuralMetadata{Schema: &Schema{Classes: map[string]Class{"a": {Name: &name}}}}
+	if _, _, err := got.MergeExtension(conflict); err == nil {
+		t.Error("MergeExtension() expected error for conflicting classes")
+	}
+}
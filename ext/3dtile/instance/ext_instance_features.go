@@ -12,6 +12,10 @@ const (
 	InstanceFeaturesExtensionName = "EXT_instance_features"
 )
 
+// PropertyTablesProperty is the name of the property tables array
+// of the EXT_structural_metadata extension in gltf.IndexRemapFunc.
+const PropertyTablesProperty = "EXT_structural_metadata.propertyTables"
+
 func init() {
 	gltf.RegisterExtension(InstanceFeaturesExtensionName, UnmarshalInstanceFeatures)
 }
@@ -34,6 +38,19 @@ type MeshExtInstanceFeatures struct {
 	Extras     json.RawMessage            `json:"extras,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the property table indices rewritten by remap.
+func (e MeshExtInstanceFeatures) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	if e.FeatureIDs != nil {
+		ids := make([]MeshExtInstanceFeatureID, len(e.FeatureIDs))
+		for i, id := range e.FeatureIDs {
+			id.PropertyTable = gltf.RemapIndex(remap, PropertyTablesProperty, id.PropertyTable)
+			ids[i] = id
+		}
+		e.FeatureIDs = ids
+	}
+	return e
+}
+
 // UnmarshalInstanceFeatures unmarshals the EXT_instance_features extension data
 func UnmarshalInstanceFeatures(data []byte) (interface{}, error) {
 	var ext MeshExtInstanceFeatures
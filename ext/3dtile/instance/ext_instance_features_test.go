@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/flywave/gltf"
+	extgltf "github.com/flywave/gltf/ext/3dtile/gltf"
+	extmesh "github.com/flywave/gltf/ext/3dtile/mesh"
 )
 
 func TestInstanceFeatures(t *testing.T) {
@@ -115,3 +117,42 @@ func TestSetInstanceFeatures(t *testing.T) {
 		t.Errorf("Expected 2 feature IDs, got %d", len(ext.FeatureIDs))
 	}
 }
+
+func TestMeshExtInstanceFeatures_Merge(t *testing.T) {
+	newDoc := func(class string) *gltf.Document {
+		return &gltf.Document{
+			Extensions: gltf.Extensions{extgltf.ExtensionName: extgltf.ExtStructuralMetadata{
+				Schema:         &extgltf.Schema{ID: "schema", Classes: map[string]extgltf.Class{class: {}}},
+				PropertyTables: []extgltf.PropertyTable{{Class: class, Count: 1}},
+			}},
+			Nodes: []*gltf.Node{{
+				Mesh: gltf.Index(0),
+				Extensions: gltf.Extensions{InstanceFeaturesExtensionName: MeshExtInstanceFeatures{
+					FeatureIDs: []MeshExtInstanceFeatureID{{FeatureCount: 1, PropertyTable: gltf.Index(0)}},
+				}},
+			}},
+			Meshes: []*gltf.Mesh{{Primitives: []*gltf.Primitive{{
+				Extensions: gltf.Extensions{extmesh.ExtensionName: extmesh.ExtMeshFeatures{
+					FeatureIDs: []extmesh.FeatureID{{FeatureCount: 1, PropertyTable: gltf.Index(0)}},
+				}},
+			}}}},
+		}
+	}
+	dst := newDoc("a")
+	if err := gltf.Merge(dst, newDoc("b")); err != nil {
+		t.Fatalf("gltf.Merge() error = %v", err)
+	}
+	if tables := dst.Extensions[extgltf.ExtensionName].(extgltf.ExtStructuralMetadata).PropertyTables; len(tables) != 2 {
+		t.Fatalf("gltf.Merge() property tables = %+v", tables)
+	}
+	for i, node := range dst.Nodes {
+		ext := node.Extensions[InstanceFeaturesExtensionName].(MeshExtInstanceFeatures)
+		if got := *ext.FeatureIDs[0].PropertyTable; got != uint32(i) {
+			t.Errorf("gltf.Merge() node %d property table = %d, want %d", i, got, i)
+		}
+		features := dst.Meshes[i].Primitives[0].Extensions[extmesh.ExtensionName].(extmesh.ExtMeshFeatures)
+		if got := *features.FeatureIDs[0].PropertyTable; got != uint32(i) {
+			t.Errorf("gltf.Merge() mesh %d property table = %d, want %d", i, got, i)
+		}
+	}
+}
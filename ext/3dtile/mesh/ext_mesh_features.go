@@ -9,8 +9,13 @@ import (
 
 const ExtensionName = "EXT_mesh_features"
 
+// PropertyTablesProperty is the name of the property tables array
+// of the EXT_structural_metadata extension in gltf.IndexRemapFunc.
+const PropertyTablesProperty = StructuralMetadataExtensionName + ".propertyTables"
+
 func init() {
 	gltf.RegisterExtension(ExtensionName, UnmarshalMeshFeatures)
+	gltf.RegisterScopedExtension(ExtensionName, gltf.ScopePrimitive, UnmarshalMeshFeatures)
 }
 
 // ExtMeshFeatures represents the EXT_mesh_features glTF Mesh Primitive extension
@@ -20,6 +25,27 @@ type ExtMeshFeatures struct {
 	Extras     json.RawMessage            `json:"extras,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture
+// and property table indices rewritten by remap.
+func (e ExtMeshFeatures) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	if e.FeatureIDs != nil {
+		ids := make([]FeatureID, len(e.FeatureIDs))
+		for i, id := range e.FeatureIDs {
+			if id.Texture != nil {
+				texture := *id.Texture
+				texture.Index = remap(gltf.PropertyTextures, texture.Index)
+				id.Texture = &texture
+			}
+			if id.PropertyTable != nil {
+				id.PropertyTable = gltf.Index(remap(PropertyTablesProperty, *id.PropertyTable))
+			}
+			ids[i] = id
+		}
+		e.FeatureIDs = ids
+	}
+	return e
+}
+
 // FeatureID represents a feature ID set
 type FeatureID struct {
 	FeatureCount  uint32                     `json:"featureCount"`
@@ -2,6 +2,8 @@ package mesh
 
 import (
 	"testing"
+
+	"github.com/flywave/gltf"
 )
 
 func TestMeshFeatures(t *testing.T) {
@@ -196,3 +198,21 @@ func TestIsDefaultChannels(t *testing.T) {
 		t.Error("IsDefaultChannels should return false for [0, 1]")
 	}
 }
+
+func TestExtMeshFeatures_RemapIndices(t *testing.T) {
+	table := uint32(0)
+	ext := ExtMeshFeatures{FeatureIDs: []FeatureID{
+		{FeatureCount: 1, PropertyTable: &table},
+		{FeatureCount: 1, Texture: &FeatureIDTexture{Index: 1}},
+	}}
+	offsets := map[string]uint32{gltf.PropertyTextures: 3, PropertyTablesProperty: 2}
+	got := ext.RemapIndices(func(property string, index uint32) uint32 {
+		return index + offsets[property]
+	}).(ExtMeshFeatures)
+	if *got.FeatureIDs[0].PropertyTable != 2 || got.FeatureIDs[1].Texture.Index != 4 {
+		t.Errorf("RemapIndices() = %+v %+v", got.FeatureIDs[0], got.FeatureIDs[1].Texture)
+	}
+	if table != 0 || ext.FeatureIDs[1].Texture.Index != 1 {
+		t.Error("RemapIndices() modified the receiver")
+	}
+}
@@ -20,6 +20,31 @@ type ExtStructuralMetadata struct {
 	Extras             json.RawMessage            `json:"extras,omitempty"`
 }
 
+// Names of the arrays defined by the document extension in gltf.IndexRemapFunc.
+const (
+	PropertyTexturesProperty   = StructuralMetadataExtensionName + ".propertyTextures"
+	PropertyAttributesProperty = StructuralMetadataExtensionName + ".propertyAttributes"
+)
+
+// RemapIndices returns a copy of the extension with the property texture
+// and property attribute indices rewritten by remap.
+func (e ExtStructuralMetadata) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	e.PropertyTextures = remapIndices(remap, PropertyTexturesProperty, e.PropertyTextures)
+	e.PropertyAttributes = remapIndices(remap, PropertyAttributesProperty, e.PropertyAttributes)
+	return e
+}
+
+func remapIndices(remap gltf.IndexRemapFunc, property string, indices []uint32) []uint32 {
+	if indices == nil {
+		return nil
+	}
+	remapped := make([]uint32, len(indices))
+	for i, index := range indices {
+		remapped[i] = remap(property, index)
+	}
+	return remapped
+}
+
 // UnmarshalExtStructuralMetadata unmarshals the EXT_structural_metadata extension data
 func UnmarshalExtStructuralMetadata(data []byte) (interface{}, error) {
 	var ext ExtStructuralMetadata
@@ -77,4 +102,5 @@ func (e *ExtStructuralMetadata) GetPropertyAttributes() []uint32 {
 
 func init() {
 	gltf.RegisterExtension(StructuralMetadataExtensionName, UnmarshalExtStructuralMetadata)
+	gltf.RegisterScopedExtension(StructuralMetadataExtensionName, gltf.ScopePrimitive, UnmarshalExtStructuralMetadata)
 }
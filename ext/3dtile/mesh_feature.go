@@ -16,6 +16,7 @@ import (
 
 func init() {
 	gltf.RegisterExtension(extmesh.ExtensionName, UnmarshalMeshFeatures)
+	gltf.RegisterScopedExtension(extmesh.ExtensionName, gltf.ScopePrimitive, UnmarshalMeshFeatures)
 }
 
 // UnmarshalMeshFeatures 反序列化EXT_mesh_features扩展数据
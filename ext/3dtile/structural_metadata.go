@@ -17,6 +17,7 @@ import (
 
 func init() {
 	gltf.RegisterExtension(extgltf.ExtensionName, UnmarshalStructuralMetadata)
+	gltf.RegisterScopedExtension(extgltf.ExtensionName, gltf.ScopeDocument, UnmarshalStructuralMetadata)
 }
 
 // UnmarshalStructuralMetadata 反序列化结构元数据扩展
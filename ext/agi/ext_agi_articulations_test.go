@@ -3,6 +3,8 @@ package agi
 import (
 	"encoding/json"
 	"testing"
+
+	"github.com/flywave/gltf"
 )
 
 func TestAgiRootArticulations(t *testing.T) {
@@ -342,3 +344,25 @@ func TestValidateAgiArticulationStage(t *testing.T) {
 		t.Error("Stage with invalid values should have failed validation")
 	}
 }
+
+func TestArticulationsScopedDecode(t *testing.T) {
+	data := []byte(`{
+		"asset": {"version": "2.0"},
+		"extensions": {"AGI_articulations": {"articulations": [{"name": "arm", "stages": []}]}},
+		"nodes": [{"extensions": {"AGI_articulations": {"isAttachPoint": true}}}]
+	}`)
+	var doc gltf.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to unmarshal document: %v", err)
+	}
+	if _, ok := doc.Extensions[ArticulationsExtensionName].(*AgiRootArticulations); !ok {
+		t.Errorf("Expected *AgiRootArticulations, got %T", doc.Extensions[ArticulationsExtensionName])
+	}
+	node, ok := doc.Nodes[0].Extensions[ArticulationsExtensionName].(*AgiNodeArticulations)
+	if !ok {
+		t.Fatalf("Expected *AgiNodeArticulations, got %T", doc.Nodes[0].Extensions[ArticulationsExtensionName])
+	}
+	if node.IsAttachPoint == nil || !*node.IsAttachPoint {
+		t.Error("Expected isAttachPoint to be true")
+	}
+}
@@ -15,9 +15,17 @@ func RegisterExtensions() {
 
 	registered = true
 
-	// Register root level extensions
+	// Register the root level extensions for any object, as a fallback
 	gltf.RegisterExtension(ArticulationsExtensionName, UnmarshalAgiRootArticulations)
 	gltf.RegisterExtension(StkMetadataExtensionName, UnmarshalAgiRootStkMetadata)
+
+	// Register root level extensions
+	gltf.RegisterScopedExtension(ArticulationsExtensionName, gltf.ScopeDocument, UnmarshalAgiRootArticulations)
+	gltf.RegisterScopedExtension(StkMetadataExtensionName, gltf.ScopeDocument, UnmarshalAgiRootStkMetadata)
+
+	// Register node level extensions
+	gltf.RegisterScopedExtension(ArticulationsExtensionName, gltf.ScopeNode, UnmarshalAgiNodeArticulations)
+	gltf.RegisterScopedExtension(StkMetadataExtensionName, gltf.ScopeNode, UnmarshalAgiNodeStkMetadata)
 }
 
 func init() {
@@ -30,6 +30,12 @@ type MaterialsAnisotropy struct {
 	AnisotropyTexture  *gltf.TextureInfo `json:"anisotropyTexture,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture indices rewritten by remap.
+func (m MaterialsAnisotropy) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	m.AnisotropyTexture = gltf.RemapTextureInfo(remap, m.AnisotropyTexture)
+	return m
+}
+
 // UnmarshalJSON unmarshal the anisotropy material with the correct default values.
 func (m *MaterialsAnisotropy) UnmarshalJSON(data []byte) error {
 	type alias MaterialsAnisotropy
@@ -2,6 +2,7 @@ package bim4d
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/flywave/gltf"
@@ -22,6 +23,22 @@ type envelop struct {
 	Version       string      `json:"version"`
 }
 
+// RemapIndices returns a copy of the extension with the buffer view
+// indices of its work items rewritten by remap.
+func (e envelop) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	if e.Works != nil {
+		works := make([]*WorkItem, len(e.Works))
+		for i, w := range e.Works {
+			if w != nil {
+				c := w.RemapIndices(remap).(WorkItem)
+				works[i] = &c
+			}
+		}
+		e.Works = works
+	}
+	return e
+}
+
 // Unmarshal decodes the json data into the correct type
 func Unmarshal(data []byte) (interface{}, error) {
 	env := envelop{Version: "1.0"}
@@ -84,6 +101,12 @@ type WorkItem struct {
 	MetadataBufferView *uint32                `json:"metadataBufferView,omitempty"` // 指向二进制元数据
 }
 
+// RemapIndices returns a copy of the work item with the metadata buffer view index rewritten by remap.
+func (w WorkItem) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	w.MetadataBufferView = gltf.RemapIndex(remap, gltf.PropertyBufferViews, w.MetadataBufferView)
+	return w
+}
+
 // CreateWorkItem creates a new WorkItem with default values
 func CreateWorkItem(info map[string]interface{}) *WorkItem {
 	item := &WorkItem{
@@ -189,38 +212,32 @@ func (w *WorkItem) UnmarshalJSON(data []byte) error {
 
 // DecodeBIM4dMetadata 处理二进制元数据解码
 func DecodeBIM4dMetadata(doc *gltf.Document) error {
+	return forEachWorkItem(doc, func(work *WorkItem) error {
+		return decodeBIM4DdMetadata(doc, work)
+	})
+}
+
+// forEachWorkItem calls fn for the work items of the nodes of doc, then for the ones of doc.
+func forEachWorkItem(doc *gltf.Document, fn func(*WorkItem) error) error {
+	exts := make([]gltf.Extensions, 0, len(doc.Nodes)+1)
 	for _, node := range doc.Nodes {
-		ext, ok := node.Extensions[ExtensionName]
-		if !ok {
+		exts = append(exts, node.Extensions)
+	}
+	exts = append(exts, doc.Extensions)
+	for _, ext := range exts {
+		env, err := gltf.GetExtension[envelop](ext, ExtensionName)
+		if errors.Is(err, gltf.ErrExtensionNotFound) {
 			continue
 		}
-
-		env, ok := ext.(envelop)
-		if !ok {
-			return fmt.Errorf("invalid %s extension format", ExtensionName)
+		if err != nil {
+			return fmt.Errorf("invalid %s extension format: %w", ExtensionName, err)
 		}
-
 		for _, work := range env.Works {
-			if err := decodeBIM4DdMetadata(doc, work); err != nil {
+			if err := fn(work); err != nil {
 				return err
 			}
 		}
 	}
-
-	ext, ok := doc.Extensions[ExtensionName]
-	if !ok {
-		return nil
-	}
-
-	env, ok := ext.(envelop)
-	if !ok {
-		return fmt.Errorf("invalid %s extension format", ExtensionName)
-	}
-	for _, work := range env.Works {
-		if err := decodeBIM4DdMetadata(doc, work); err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
@@ -252,39 +269,9 @@ func decodeBIM4DdMetadata(doc *gltf.Document, work *WorkItem) error {
 
 // EncodeBIM4dMetadata 处理二进制元数据编码
 func EncodeBIM4dMetadata(doc *gltf.Document) error {
-	for _, node := range doc.Nodes {
-		ext, ok := node.Extensions[ExtensionName]
-		if !ok {
-			continue
-		}
-
-		env, ok := ext.(envelop)
-		if !ok {
-			return fmt.Errorf("invalid %s extension format", ExtensionName)
-		}
-
-		for _, work := range env.Works {
-			if err := encodeBIM4DdMetadata(doc, work); err != nil {
-				return err
-			}
-		}
-	}
-
-	ext, ok := doc.Extensions[ExtensionName]
-	if !ok {
-		return nil
-	}
-
-	env, ok := ext.(envelop)
-	if !ok {
-		return fmt.Errorf("invalid %s extension format", ExtensionName)
-	}
-	for _, work := range env.Works {
-		if err := encodeBIM4DdMetadata(doc, work); err != nil {
-			return err
-		}
-	}
-	return nil
+	return forEachWorkItem(doc, func(work *WorkItem) error {
+		return encodeBIM4DdMetadata(doc, work)
+	})
 }
 
 func encodeBIM4DdMetadata(doc *gltf.Document, work *WorkItem) error {
@@ -317,38 +304,42 @@ func encodeBIM4DdMetadata(doc *gltf.Document, work *WorkItem) error {
 
 // AddWorkItemToNode 添加工作项到节点
 func AddWorkItemToNode(node *gltf.Node, work *WorkItem) {
-	if node.Extensions == nil {
-		node.Extensions = make(map[string]interface{})
+	if env, ok := nodeEnvelop(node); ok {
+		env.Works = append(env.Works, work)
+		setNodeEnvelop(node, env)
 	}
+}
 
-	ext, ok := node.Extensions[ExtensionName]
-	if !ok {
-		ext = envelop{
-			Version: "1.0",
-			Works:   []*WorkItem{work},
-		}
-		node.Extensions[ExtensionName] = ext
-		return
+// WriteWorkItem 添加工作项到节点，并将扩展记录到文档的 extensionsUsed 中
+func WriteWorkItem(doc *gltf.Document, node *gltf.Node, work *WorkItem) {
+	if env, ok := nodeEnvelop(node); ok {
+		env.Works = append(env.Works, work)
+		gltf.SetExtension(doc, &node.Extensions, ExtensionName, env)
 	}
+}
 
-	env, ok := ext.(envelop)
-	if !ok {
-		return
+// nodeEnvelop returns the extension of node, or an empty one if node has none.
+// It returns false if the extension can't be decoded.
+func nodeEnvelop(node *gltf.Node) (envelop, bool) {
+	env, err := gltf.GetExtension[envelop](node.Extensions, ExtensionName)
+	if errors.Is(err, gltf.ErrExtensionNotFound) {
+		return envelop{Version: "1.0"}, true
 	}
+	return env, err == nil
+}
 
-	env.Works = append(env.Works, work)
+// setNodeEnvelop stores env as the extension of node.
+func setNodeEnvelop(node *gltf.Node, env envelop) {
+	if node.Extensions == nil {
+		node.Extensions = make(gltf.Extensions)
+	}
 	node.Extensions[ExtensionName] = env
 }
 
 // GetWorkItemsFromNode 从节点获取工作项
 func GetWorkItemsFromNode(node *gltf.Node) []*WorkItem {
-	ext, ok := node.Extensions[ExtensionName]
-	if !ok {
-		return nil
-	}
-
-	env, ok := ext.(envelop)
-	if !ok {
+	env, err := gltf.GetExtension[envelop](node.Extensions, ExtensionName)
+	if err != nil {
 		return nil
 	}
 
@@ -357,42 +348,24 @@ func GetWorkItemsFromNode(node *gltf.Node) []*WorkItem {
 
 // SetCurrentWorkItem 设置节点的当前工作项
 func SetCurrentWorkItem(node *gltf.Node, workID string) {
-	if node.Extensions == nil {
-		node.Extensions = make(map[string]interface{})
-	}
-
-	ext, ok := node.Extensions[ExtensionName]
-	if !ok {
-		ext = envelop{
-			Version:       "1.0",
-			CurrentWorkID: &workID,
-		}
-		node.Extensions[ExtensionName] = ext
-		return
+	if env, ok := nodeEnvelop(node); ok {
+		env.CurrentWorkID = &workID
+		setNodeEnvelop(node, env)
 	}
+}
 
-	env, ok := ext.(envelop)
-	if !ok {
-		return
+// WriteCurrentWorkItem 设置节点的当前工作项，并将扩展记录到文档的 extensionsUsed 中
+func WriteCurrentWorkItem(doc *gltf.Document, node *gltf.Node, workID string) {
+	if env, ok := nodeEnvelop(node); ok {
+		env.CurrentWorkID = &workID
+		gltf.SetExtension(doc, &node.Extensions, ExtensionName, env)
 	}
-
-	env.CurrentWorkID = &workID
-	node.Extensions[ExtensionName] = env
 }
 
 // GetCurrentWorkItem 获取节点的当前工作项
 func GetCurrentWorkItem(node *gltf.Node) *WorkItem {
-	ext, ok := node.Extensions[ExtensionName]
-	if !ok {
-		return nil
-	}
-
-	env, ok := ext.(envelop)
-	if !ok {
-		return nil
-	}
-
-	if env.CurrentWorkID == nil {
+	env, err := gltf.GetExtension[envelop](node.Extensions, ExtensionName)
+	if err != nil || env.CurrentWorkID == nil {
 		return nil
 	}
 
@@ -448,11 +421,7 @@ func GetWorkItems(doc *gltf.Document) []*WorkItem {
 	for _, node := range doc.Nodes {
 		wks = append(wks, GetWorkItemsFromNode(node)...)
 	}
-	for _, ext := range doc.Extensions {
-		env, ok := ext.(envelop)
-		if !ok {
-			continue
-		}
+	if env, err := gltf.GetExtension[envelop](doc.Extensions, ExtensionName); err == nil {
 		wks = append(wks, env.Works...)
 	}
 	return wks
@@ -465,13 +434,9 @@ func WriteInstanceBim4d(doc *gltf.Document, props []map[string]interface{}) erro
 
 	for i, node := range doc.Nodes {
 		info := CreateWorkItem(props[i])
-		AddWorkItemToNode(node, info)
+		WriteWorkItem(doc, node, info)
 	}
-	if err := EncodeBIM4dMetadata(doc); err != nil {
-		return err
-	}
-	doc.AddExtensionUsed(ExtensionName)
-	return nil
+	return EncodeBIM4dMetadata(doc)
 }
 
 func WriteBatchModelBim4d(doc *gltf.Document, props []map[string]interface{}) error {
@@ -481,30 +446,15 @@ func WriteBatchModelBim4d(doc *gltf.Document, props []map[string]interface{}) er
 		wks = append(wks, info)
 	}
 
-	ext, ok := doc.Extensions[ExtensionName]
-	if !ok {
-		ext = envelop{
-			Version: "1.0",
-			Works:   wks,
-		}
-		if len(doc.Extensions) == 0 {
-			doc.Extensions = make(map[string]interface{})
-		}
-		doc.Extensions[ExtensionName] = ext
-	} else {
-		// 如果扩展已存在，替换工作项而不是追加
-		env, ok := ext.(envelop)
-		if !ok {
-			return nil
-		}
-		env.Works = wks
-		doc.Extensions[ExtensionName] = env
+	env, err := gltf.GetExtension[envelop](doc.Extensions, ExtensionName)
+	if errors.Is(err, gltf.ErrExtensionNotFound) {
+		env = envelop{Version: "1.0"}
+	} else if err != nil {
+		return fmt.Errorf("invalid %s extension format: %w", ExtensionName, err)
 	}
+	// 如果扩展已存在，替换工作项而不是追加
+	env.Works = wks
+	gltf.SetExtension(doc, &doc.Extensions, ExtensionName, env)
 
-	if err := EncodeBIM4dMetadata(doc); err != nil {
-		return err
-	}
-
-	doc.AddExtensionUsed(ExtensionName)
-	return nil
+	return EncodeBIM4dMetadata(doc)
 }
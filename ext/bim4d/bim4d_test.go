@@ -655,6 +655,21 @@ func TestAddWorkItemToNode(t *testing.T) {
 	}
 }
 
+func TestWriteWorkItem(t *testing.T) {
+	doc := new(gltf.Document)
+	node := new(gltf.Node)
+
+	WriteWorkItem(doc, node, &WorkItem{ID: "work1"})
+	WriteCurrentWorkItem(doc, node, "work1")
+
+	if len(doc.ExtensionsUsed) != 1 || doc.ExtensionsUsed[0] != ExtensionName {
+		t.Errorf("Expected %s to be used, got %v", ExtensionName, doc.ExtensionsUsed)
+	}
+	if current := GetCurrentWorkItem(node); current == nil || current.ID != "work1" {
+		t.Errorf("Expected current work item 'work1', got %v", current)
+	}
+}
+
 func TestGetWorkItemsFromNode(t *testing.T) {
 	// Create a node with work items
 	node := &gltf.Node{
@@ -916,6 +931,29 @@ func TestEncodeDecodeBIM4dMetadata(t *testing.T) {
 	}
 }
 
+func TestDecodeBIM4dMetadata_Payload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		wantErr bool
+	}{
+		{"raw", json.RawMessage(`{"version":"1.0","works":[{"id":"work1"}]}`), false},
+		{"pointer", &envelop{Works: []*WorkItem{{ID: "work1"}}}, false},
+		{"invalid", "work1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &gltf.Document{Nodes: []*gltf.Node{{Extensions: gltf.Extensions{ExtensionName: tt.payload}}}}
+			if err := DecodeBIM4dMetadata(doc); (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeBIM4dMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if works := GetWorkItemsFromNode(doc.Nodes[0]); !tt.wantErr && (len(works) != 1 || works[0].ID != "work1") {
+				t.Errorf("GetWorkItemsFromNode() = %v", works)
+			}
+		})
+	}
+}
+
 func TestWriteInstanceBim4d(t *testing.T) {
 	// Create a document with nodes
 	doc := &gltf.Document{
@@ -1137,3 +1175,20 @@ func TestGetWorkItems(t *testing.T) {
 		t.Error("Work item 'work3' not found")
 	}
 }
+
+func TestEnvelop_RemapIndices(t *testing.T) {
+	work := &WorkItem{ID: "work1", MetadataBufferView: gltf.Index(2)}
+	env := envelop{Version: "1.0", Works: []*WorkItem{work, {ID: "work2"}}}
+	got := env.RemapIndices(func(property string, index uint32) uint32 {
+		if property != gltf.PropertyBufferViews {
+			t.Errorf("RemapIndices() property = %s", property)
+		}
+		return index + 1
+	}).(envelop)
+	if *got.Works[0].MetadataBufferView != 3 || got.Works[1].MetadataBufferView != nil {
+		t.Errorf("RemapIndices() = %+v", got.Works)
+	}
+	if *work.MetadataBufferView != 2 {
+		t.Error("RemapIndices() modified the receiver")
+	}
+}
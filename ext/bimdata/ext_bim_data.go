@@ -21,6 +21,12 @@ type BimData struct {
 	Extras     json.RawMessage            `json:"extras,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the buffer view index rewritten by remap.
+func (b BimData) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	b.BufferView = gltf.RemapIndex(remap, gltf.PropertyBufferViews, b.BufferView)
+	return b
+}
+
 // BimDataRoot represents the root GRIFFEL_bim_data extension
 type BimDataRoot struct {
 	PropertyNames  []string                   `json:"propertyNames"`
@@ -32,6 +38,19 @@ type BimDataRoot struct {
 	Extras         json.RawMessage            `json:"extras,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the node indices rewritten by remap.
+func (r BimDataRoot) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	if r.NodeProperties != nil {
+		mappings := make([]NodePropertyMapping, len(r.NodeProperties))
+		for i, m := range r.NodeProperties {
+			m.Node = remap(gltf.PropertyNodes, m.Node)
+			mappings[i] = m
+		}
+		r.NodeProperties = mappings
+	}
+	return r
+}
+
 // BimProperty represents a single property with name and value indices
 type BimProperty struct {
 	Name  uint32 `json:"name"`
@@ -130,6 +149,7 @@ func (e *BimDataRoot) AddNodePropertyMapping(nodeIndex uint32, propertyIndices [
 }
 
 func init() {
-	gltf.RegisterExtension(BimDataExtensionName, UnmarshalBimData)
 	gltf.RegisterExtension(BimDataExtensionName, UnmarshalBimDataRoot)
+	gltf.RegisterScopedExtension(BimDataExtensionName, gltf.ScopeNode, UnmarshalBimData)
+	gltf.RegisterScopedExtension(BimDataExtensionName, gltf.ScopeDocument, UnmarshalBimDataRoot)
 }
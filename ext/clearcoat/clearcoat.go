@@ -32,6 +32,14 @@ type MaterialsClearcoat struct {
 	ClearcoatNormalTexture    *gltf.NormalTexture `json:"clearcoatNormalTexture,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture indices rewritten by remap.
+func (m MaterialsClearcoat) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	m.ClearcoatTexture = gltf.RemapTextureInfo(remap, m.ClearcoatTexture)
+	m.ClearcoatRoughnessTexture = gltf.RemapTextureInfo(remap, m.ClearcoatRoughnessTexture)
+	m.ClearcoatNormalTexture = gltf.RemapNormalTexture(remap, m.ClearcoatNormalTexture)
+	return m
+}
+
 // UnmarshalJSON unmarshal the clearcoat material with the correct default values.
 func (m *MaterialsClearcoat) UnmarshalJSON(data []byte) error {
 	type alias MaterialsClearcoat
@@ -25,6 +25,13 @@ type DracoExtension struct {
 	Attributes map[string]uint32 `json:"attributes"`
 }
 
+// RemapIndices returns a copy of the extension with the buffer view index rewritten by remap.
+// Attributes hold Draco attribute ids and are kept as is.
+func (e DracoExtension) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	e.BufferView = remap(gltf.PropertyBufferViews, e.BufferView)
+	return e
+}
+
 func Unmarshal(data []byte) (interface{}, error) {
 	ext := &DracoExtension{}
 	if err := json.Unmarshal(data, ext); err != nil {
@@ -0,0 +1,391 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/flywave/gltf"
+	"github.com/flywave/gltf/ext/instance"
+	"github.com/flywave/gltf/modeler"
+)
+
+// Bounds is an axis-aligned bounding box together with a bounding sphere.
+type Bounds struct {
+	Min    [3]float64
+	Max    [3]float64
+	Center [3]float64 // Center of the bounding sphere.
+	Radius float64    // Radius of the bounding sphere.
+}
+
+// union returns the bounds containing b and o, any of which can be nil.
+func (b *Bounds) union(o *Bounds) *Bounds {
+	if b == nil {
+		return o
+	}
+	if o == nil {
+		return b
+	}
+	u := &Bounds{}
+	for i := 0; i < 3; i++ {
+		u.Min[i] = math.Min(b.Min[i], o.Min[i])
+		u.Max[i] = math.Max(b.Max[i], o.Max[i])
+	}
+	d := distance(b.Center, o.Center)
+	switch {
+	case d+o.Radius <= b.Radius:
+		u.Center, u.Radius = b.Center, b.Radius
+	case d+b.Radius <= o.Radius:
+		u.Center, u.Radius = o.Center, o.Radius
+	default:
+		u.Radius = (d + b.Radius + o.Radius) / 2
+		t := (u.Radius - b.Radius) / d
+		for i := 0; i < 3; i++ {
+			u.Center[i] = b.Center[i] + (o.Center[i]-b.Center[i])*t
+		}
+	}
+	return u
+}
+
+// transform returns the bounds of b transformed by the column-major matrix m.
+// The box contains the 8 transformed corners of b and the sphere is scaled
+// by the largest scale factor of m.
+func (b *Bounds) transform(m [16]float32) *Bounds {
+	if b == nil {
+		return nil
+	}
+	t := &Bounds{}
+	for i := 0; i < 8; i++ {
+		corner := [3]float64{b.Min[0], b.Min[1], b.Min[2]}
+		for j := 0; j < 3; j++ {
+			if i&(1<<j) != 0 {
+				corner[j] = b.Max[j]
+			}
+		}
+		p := transformPoint(m, corner)
+		if i == 0 {
+			t.Min, t.Max = p, p
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			t.Min[j] = math.Min(t.Min[j], p[j])
+			t.Max[j] = math.Max(t.Max[j], p[j])
+		}
+	}
+	var scale float64
+	for j := 0; j < 3; j++ {
+		scale = math.Max(scale, math.Sqrt(float64(m[j*4])*float64(m[j*4])+float64(m[j*4+1])*float64(m[j*4+1])+float64(m[j*4+2])*float64(m[j*4+2])))
+	}
+	t.Center = transformPoint(m, b.Center)
+	t.Radius = b.Radius * scale
+	return t
+}
+
+// PrimitiveBounds returns the bounds of the positions of p in the space of the mesh,
+// or nil if p has no POSITION attribute.
+//
+// Quantized positions are decoded, taking the normalized flag into account.
+// When p has morph targets the bounds contain every position reachable
+// with weights between 0 and 1.
+func PrimitiveBounds(doc *gltf.Document, p *gltf.Primitive) (*Bounds, error) {
+	index, ok := p.Attributes[gltf.POSITION]
+	if !ok {
+		return nil, nil
+	}
+	positions, err := readVec3(doc, index)
+	if err != nil || len(positions) == 0 {
+		return nil, err
+	}
+	// lo and hi are the extreme positions of each vertex under any target weights.
+	lo := positions
+	hi := make([][3]float64, len(positions))
+	copy(hi, positions)
+	if len(p.Targets) > 0 {
+		lo = make([][3]float64, len(positions))
+		copy(lo, positions)
+	}
+	for _, target := range p.Targets {
+		index, ok := target[gltf.POSITION]
+		if !ok {
+			continue
+		}
+		deltas, err := readVec3(doc, index)
+		if err != nil {
+			return nil, err
+		}
+		if len(deltas) != len(positions) {
+			return nil, fmt.Errorf("gltf: morph target has %d positions instead of %d", len(deltas), len(positions))
+		}
+		for i, d := range deltas {
+			for j := 0; j < 3; j++ {
+				if d[j] < 0 {
+					lo[i][j] += d[j]
+				} else {
+					hi[i][j] += d[j]
+				}
+			}
+		}
+	}
+
+	b := &Bounds{Min: lo[0], Max: hi[0]}
+	for i := range lo {
+		for j := 0; j < 3; j++ {
+			b.Min[j] = math.Min(b.Min[j], lo[i][j])
+			b.Max[j] = math.Max(b.Max[j], hi[i][j])
+		}
+	}
+	for j := 0; j < 3; j++ {
+		b.Center[j] = (b.Min[j] + b.Max[j]) / 2
+	}
+	for i := range lo {
+		// Farthest point of the box spanned by the vertex.
+		var far [3]float64
+		for j := 0; j < 3; j++ {
+			far[j] = lo[i][j]
+			if math.Abs(hi[i][j]-b.Center[j]) > math.Abs(lo[i][j]-b.Center[j]) {
+				far[j] = hi[i][j]
+			}
+		}
+		b.Radius = math.Max(b.Radius, distance(b.Center, far))
+	}
+	return b, nil
+}
+
+// MeshBounds returns the union of the bounds of the primitives of mesh,
+// or nil if none of them has positions.
+func MeshBounds(doc *gltf.Document, mesh *gltf.Mesh) (*Bounds, error) {
+	var b *Bounds
+	for _, p := range mesh.Primitives {
+		pb, err := PrimitiveBounds(doc, p)
+		if err != nil {
+			return nil, err
+		}
+		b = b.union(pb)
+	}
+	return b, nil
+}
+
+// NodeBounds returns the world space bounds of the meshes of the node at index
+// and of all its descendants, including the instances defined by EXT_mesh_gpu_instancing,
+// or nil if they have no geometry.
+func NodeBounds(doc *gltf.Document, index uint32) (*Bounds, error) {
+	return hierarchyBounds(doc, []uint32{index})
+}
+
+// ComputeSceneBounds returns the world space bounds of all the nodes of the scene at index,
+// or nil if the scene has no geometry. See NodeBounds.
+func ComputeSceneBounds(doc *gltf.Document, index uint32) (*Bounds, error) {
+	if int(index) >= len(doc.Scenes) {
+		return nil, fmt.Errorf("gltf: scene %d does not exist", index)
+	}
+	return hierarchyBounds(doc, doc.Scenes[index].Nodes)
+}
+
+func hierarchyBounds(doc *gltf.Document, roots []uint32) (*Bounds, error) {
+	var b *Bounds
+	err := walkMeshes(doc, roots, func(_ uint32, mesh *gltf.Mesh, matrices [][16]float32) error {
+		mb, err := MeshBounds(doc, mesh)
+		if err != nil {
+			return err
+		}
+		for _, m := range matrices {
+			b = b.union(mb.transform(m))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// FillGeometryMetadata computes the FB_geometry_metadata of the scene at index
+// and stores it in the scene, keeping the extensions and extras of an existing payload.
+//
+// The vertex count is the number of POSITION elements and the primitive count
+// the number of points, lines and triangles drawn by every node of the scene,
+// each instance of a mesh counting once.
+func FillGeometryMetadata(doc *gltf.Document, index uint32) (*FbGeometryMetadata, error) {
+	if int(index) >= len(doc.Scenes) {
+		return nil, fmt.Errorf("gltf: scene %d does not exist", index)
+	}
+	scene := doc.Scenes[index]
+	bounds, err := ComputeSceneBounds(doc, index)
+	if err != nil {
+		return nil, err
+	}
+	var vertices, primitives float64
+	err = walkMeshes(doc, scene.Nodes, func(_ uint32, mesh *gltf.Mesh, matrices [][16]float32) error {
+		for _, p := range mesh.Primitives {
+			v, n := primitiveCounts(doc, p)
+			vertices += float64(v) * float64(len(matrices))
+			primitives += float64(n) * float64(len(matrices))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := gltf.GetExtension[*FbGeometryMetadata](scene.Extensions, FbGeometryMetadataExtensionName)
+	if err != nil {
+		metadata = new(FbGeometryMetadata)
+	}
+	metadata.SetVertexCount(vertices)
+	metadata.SetPrimitiveCount(primitives)
+	metadata.SceneBounds = nil
+	if bounds != nil {
+		metadata.SetSceneBounds(SceneBounds{
+			Min: bounds.Min[:],
+			Max: bounds.Max[:],
+		})
+	}
+	gltf.SetExtension(doc, &scene.Extensions, FbGeometryMetadataExtensionName, metadata)
+	return metadata, nil
+}
+
+// walkMeshes calls fn for every node with a mesh in the hierarchies rooted at roots,
+// with the world matrix of each of its instances.
+func walkMeshes(doc *gltf.Document, roots []uint32, fn func(index uint32, mesh *gltf.Mesh, matrices [][16]float32) error) error {
+	world, err := gltf.WorldMatrices(doc)
+	if err != nil {
+		return err
+	}
+	return gltf.WalkDepthFirst(doc, roots, func(index uint32, n *gltf.Node, _ []uint32) error {
+		if n.Mesh == nil {
+			return nil
+		}
+		if int(*n.Mesh) >= len(doc.Meshes) {
+			return fmt.Errorf("gltf: mesh %d does not exist", *n.Mesh)
+		}
+		matrices := [][16]float32{world[index]}
+		// Decode the payload first, as ReadInstancing only accepts decoded ones.
+		if _, err := gltf.GetExtension[*instance.InstanceAttributes](n.Extensions, instance.ExtensionName); err != nil {
+			if !errors.Is(err, gltf.ErrExtensionNotFound) {
+				return err
+			}
+		} else {
+			data, err := instance.ReadInstancing(doc, index)
+			if err != nil {
+				return err
+			}
+			matrices = make([][16]float32, data.InstanceCount())
+			for i := range matrices {
+				t, r, s := [3]float32{}, [4]float32{0, 0, 0, 1}, [3]float32{1, 1, 1}
+				if i < len(data.Translations) {
+					t = data.Translations[i]
+				}
+				if i < len(data.Rotations) {
+					r = data.Rotations[i]
+				}
+				if i < len(data.Scales) {
+					s = data.Scales[i]
+				}
+				matrices[i] = mulMatrix(world[index], gltf.ComposeMatrix(t, r, s))
+			}
+		}
+		return fn(index, doc.Meshes[*n.Mesh], matrices)
+	})
+}
+
+// primitiveCounts returns the number of vertices of p and the number of
+// points, lines or triangles it draws.
+func primitiveCounts(doc *gltf.Document, p *gltf.Primitive) (vertices, primitives uint32) {
+	if index, ok := p.Attributes[gltf.POSITION]; ok && int(index) < len(doc.Accessors) {
+		vertices = doc.Accessors[index].Count
+	}
+	n := vertices
+	if p.Indices != nil && int(*p.Indices) < len(doc.Accessors) {
+		n = doc.Accessors[*p.Indices].Count
+	}
+	switch p.Mode {
+	case gltf.PrimitivePoints:
+		primitives = n
+	case gltf.PrimitiveLines:
+		primitives = n / 2
+	case gltf.PrimitiveLineLoop:
+		if n > 1 {
+			primitives = n
+		}
+	case gltf.PrimitiveLineStrip:
+		if n > 1 {
+			primitives = n - 1
+		}
+	case gltf.PrimitiveTriangleStrip, gltf.PrimitiveTriangleFan:
+		if n > 2 {
+			primitives = n - 2
+		}
+	default:
+		primitives = n / 3
+	}
+	return vertices, primitives
+}
+
+// readVec3 returns the elements of the VEC3 accessor at index as float64,
+// decoding normalized integer components.
+func readVec3(doc *gltf.Document, index uint32) ([][3]float64, error) {
+	if int(index) >= len(doc.Accessors) {
+		return nil, fmt.Errorf("gltf: accessor %d does not exist", index)
+	}
+	acr := doc.Accessors[index]
+	if acr.Type != gltf.AccessorVec3 {
+		return nil, fmt.Errorf("gltf: accessor %d type is %s instead of VEC3", index, acr.Type)
+	}
+	data, err := modeler.ReadAccessor(doc, acr, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][3]float64, acr.Count)
+	norm := func(v, max float64) float64 {
+		if acr.Normalized {
+			return math.Max(v/max, -1)
+		}
+		return v
+	}
+	for i := range out {
+		for j := 0; j < 3; j++ {
+			switch data := data.(type) {
+			case [][3]float32:
+				out[i][j] = float64(data[i][j])
+			case [][3]int8:
+				out[i][j] = norm(float64(data[i][j]), math.MaxInt8)
+			case [][3]uint8:
+				out[i][j] = norm(float64(data[i][j]), math.MaxUint8)
+			case [][3]int16:
+				out[i][j] = norm(float64(data[i][j]), math.MaxInt16)
+			case [][3]uint16:
+				out[i][j] = norm(float64(data[i][j]), math.MaxUint16)
+			case [][3]uint32:
+				out[i][j] = float64(data[i][j])
+			}
+		}
+	}
+	return out, nil
+}
+
+func transformPoint(m [16]float32, p [3]float64) [3]float64 {
+	var out [3]float64
+	for i := 0; i < 3; i++ {
+		out[i] = float64(m[i])*p[0] + float64(m[4+i])*p[1] + float64(m[8+i])*p[2] + float64(m[12+i])
+	}
+	return out
+}
+
+// mulMatrix returns a*b, both column-major.
+func mulMatrix(a, b [16]float32) [16]float32 {
+	var m [16]float32
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			var v float32
+			for k := 0; k < 4; k++ {
+				v += a[k*4+r] * b[c*4+k]
+			}
+			m[c*4+r] = v
+		}
+	}
+	return m
+}
+
+func distance(a, b [3]float64) float64 {
+	return math.Sqrt((a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1]) + (a[2]-b[2])*(a[2]-b[2]))
+}
@@ -0,0 +1,144 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flywave/gltf"
+	"github.com/flywave/gltf/ext/instance"
+	"github.com/flywave/gltf/modeler"
+)
+
+func boundsAlmostEqual(a, b *Bounds) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	eq := func(x, y float64) bool { return math.Abs(x-y) < 1e-5 }
+	for i := 0; i < 3; i++ {
+		if !eq(a.Min[i], b.Min[i]) || !eq(a.Max[i], b.Max[i]) || !eq(a.Center[i], b.Center[i]) {
+			return false
+		}
+	}
+	return eq(a.Radius, b.Radius)
+}
+
+func TestPrimitiveBounds(t *testing.T) {
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {2, 0, 0}, {0, 2, 0}})
+	up := modeler.WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{0, 0, 1}, {0, 0, 0}, {0, 0, 0}})
+	down := modeler.WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{0, 0, 0}, {0, 0, -1}, {0, 0, 0}})
+	quantized := modeler.WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]int16{{32767, 0, 0}, {-32767, 0, 0}})
+	doc.Accessors[quantized].Normalized = true
+	tests := []struct {
+		name string
+		p    *gltf.Primitive
+		want *Bounds
+	}{
+		{"no position", &gltf.Primitive{}, nil},
+		{"float", &gltf.Primitive{Attributes: gltf.Attribute{gltf.POSITION: pos}}, &Bounds{
+			Max: [3]float64{2, 2, 0}, Center: [3]float64{1, 1, 0}, Radius: math.Sqrt2,
+		}},
+		{"targets", &gltf.Primitive{
+			Attributes: gltf.Attribute{gltf.POSITION: pos},
+			Targets:    []gltf.Attribute{{gltf.POSITION: up}, {gltf.POSITION: down}},
+		}, &Bounds{
+			Min: [3]float64{0, 0, -1}, Max: [3]float64{2, 2, 1}, Center: [3]float64{1, 1, 0}, Radius: math.Sqrt(3),
+		}},
+		{"normalized", &gltf.Primitive{Attributes: gltf.Attribute{gltf.POSITION: quantized}}, &Bounds{
+			Min: [3]float64{-1, 0, 0}, Max: [3]float64{1, 0, 0}, Radius: 1,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PrimitiveBounds(doc, tt.p)
+			if err != nil {
+				t.Fatalf("PrimitiveBounds() error = %v", err)
+			}
+			if !boundsAlmostEqual(got, tt.want) {
+				t.Errorf("PrimitiveBounds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeSceneBounds(t *testing.T) {
+	want := &Bounds{Min: [3]float64{8, -2, -2}, Max: [3]float64{12, 2, 2}, Center: [3]float64{10, 0, 0}, Radius: 2 * math.Sqrt(3)}
+	tests := []struct {
+		name  string
+		check func(t *testing.T, doc *gltf.Document)
+	}{
+		{"scene", func(t *testing.T, doc *gltf.Document) {
+			got, err := ComputeSceneBounds(doc, 0)
+			if err != nil {
+				t.Fatalf("ComputeSceneBounds() error = %v", err)
+			}
+			if !boundsAlmostEqual(got, want) {
+				t.Errorf("ComputeSceneBounds() = %v, want %v", got, want)
+			}
+		}},
+		{"node", func(t *testing.T, doc *gltf.Document) {
+			got, err := NodeBounds(doc, 1)
+			if err != nil {
+				t.Fatalf("NodeBounds() error = %v", err)
+			}
+			if !boundsAlmostEqual(got, want) {
+				t.Errorf("NodeBounds() = %v, want %v", got, want)
+			}
+		}},
+		{"missingScene", func(t *testing.T, doc *gltf.Document) {
+			if _, err := ComputeSceneBounds(doc, 1); err == nil {
+				t.Error("ComputeSceneBounds() expected error")
+			}
+		}},
+		{"instancing", func(t *testing.T, doc *gltf.Document) {
+			doc.Nodes[0].Children = nil
+			translations := modeler.WriteAccessor(doc, gltf.TargetNone, [][3]float32{{0, 0, 0}, {0, 5, 0}})
+			if err := instance.WriteInstanceExtension(doc, doc.Nodes[0], map[string]uint32{"TRANSLATION": translations}); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ComputeSceneBounds(doc, 0)
+			if err != nil {
+				t.Fatalf("ComputeSceneBounds() error = %v", err)
+			}
+			if got.Min != [3]float64{9, -1, -1} || got.Max != [3]float64{11, 6, 1} {
+				t.Errorf("ComputeSceneBounds() = %v", got)
+			}
+		}},
+		{"geometryMetadata", func(t *testing.T, doc *gltf.Document) {
+			doc.Scenes[0].Extensions = gltf.Extensions{FbGeometryMetadataExtensionName: &FbGeometryMetadata{Extras: []byte(`{"a":1}`)}}
+			metadata, err := FillGeometryMetadata(doc, 0)
+			if err != nil {
+				t.Fatalf("FillGeometryMetadata() error = %v", err)
+			}
+			if *metadata.VertexCount != 4 || *metadata.PrimitiveCount != 4 {
+				t.Errorf("FillGeometryMetadata() counts = %v, %v", *metadata.VertexCount, *metadata.PrimitiveCount)
+			}
+			if b := metadata.SceneBounds; b == nil || b.Min[0] != 8 || b.Max[0] != 12 {
+				t.Errorf("FillGeometryMetadata() bounds = %v", b)
+			}
+			if string(metadata.Extras) != `{"a":1}` {
+				t.Error("FillGeometryMetadata() expected extras to be kept")
+			}
+			if doc.Scenes[0].Extensions[FbGeometryMetadataExtensionName] != metadata || !doc.HasExtensionUsed(FbGeometryMetadataExtensionName) {
+				t.Error("FillGeometryMetadata() expected the extension to be stored")
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gltf.NewDocument()
+			pos := modeler.WritePosition(doc, [][3]float32{{-1, -1, -1}, {1, 1, 1}})
+			indices := modeler.WriteIndices(doc, []uint16{0, 1, 0, 1, 0, 1})
+			doc.Meshes = []*gltf.Mesh{{Primitives: []*gltf.Primitive{{
+				Attributes: gltf.Attribute{gltf.POSITION: pos},
+				Indices:    gltf.Index(indices),
+			}}}}
+			doc.Nodes = []*gltf.Node{
+				{Mesh: gltf.Index(0), Translation: [3]float32{10, 0, 0}, Children: []uint32{1}},
+				{Mesh: gltf.Index(0), Scale: [3]float32{2, 2, 2}},
+			}
+			doc.Scenes = []*gltf.Scene{{Nodes: []uint32{0}}}
+			tt.check(t, doc)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/flywave/gltf"
+	"github.com/flywave/gltf/modeler"
 	"github.com/flywave/go3d/mat4"
 	"github.com/flywave/go3d/quaternion"
 	"github.com/flywave/go3d/vec3"
@@ -16,6 +17,7 @@ const ExtensionName = "EXT_mesh_gpu_instancing"
 
 func init() {
 	gltf.RegisterExtension(ExtensionName, Unmarshal)
+	gltf.RegisterScopedExtension(ExtensionName, gltf.ScopeNode, Unmarshal)
 }
 
 // 扩展数据结构
@@ -23,6 +25,15 @@ type InstanceAttributes struct {
 	Attributes map[string]uint32 `json:"attributes"`
 }
 
+// RemapIndices returns a copy of the attributes with the accessor indices rewritten by remap.
+func (a InstanceAttributes) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	attrs := make(map[string]uint32, len(a.Attributes))
+	for name, index := range a.Attributes {
+		attrs[name] = remap(gltf.PropertyAccessors, index)
+	}
+	return InstanceAttributes{Attributes: attrs}
+}
+
 // 用于解析的包装结构
 type instanceEnvelope struct {
 	Attributes *InstanceAttributes `json:"attributes"`
@@ -230,19 +241,7 @@ func ReadInstancing(doc *gltf.Document, nodeIndex uint32) (*InstanceData, error)
 
 	// 处理ROTATION属性
 	if idx, exists := attrsData["ROTATION"]; exists {
-		accessor := doc.Accessors[idx]
-		// 根据组件类型使用不同的读取方法
-		switch accessor.ComponentType {
-		case gltf.ComponentFloat:
-			data.Rotations, _, err = readVec4Data(doc, idx)
-		case gltf.ComponentByte:
-			data.Rotations, _, err = readNormalizedVec4Byte(doc, idx)
-		case gltf.ComponentShort:
-			data.Rotations, _, err = readNormalizedVec4Short(doc, idx)
-		default:
-			err = fmt.Errorf("不支持的ROTATION组件类型: %v", accessor.ComponentType)
-		}
-
+		data.Rotations, _, err = readVec4Data(doc, idx)
 		if err != nil {
 			return nil, fmt.Errorf("读取ROTATION失败: %w", err)
 		}
@@ -281,6 +280,12 @@ func parseAttributes(ext interface{}) (map[string]uint32, error) {
 		return v.Attributes, nil
 	case InstanceAttributes:
 		return v.Attributes, nil
+	case []byte, json.RawMessage:
+		attrs, err := gltf.GetExtension[*InstanceAttributes](gltf.Extensions{ExtensionName: v}, ExtensionName)
+		if err != nil {
+			return nil, err
+		}
+		return attrs.Attributes, nil
 	case map[string]interface{}:
 		attrs := make(map[string]uint32)
 		for key, val := range v {
@@ -298,192 +303,51 @@ func parseAttributes(ext interface{}) (map[string]uint32, error) {
 	}
 }
 
-// 读取三维向量数据 (FLOAT)
+// 读取三维向量数据 (TRANSLATION, SCALE)
 func readVec3Data(doc *gltf.Document, accessorIdx uint32) ([][3]float32, uint32, error) {
-	accessor := doc.Accessors[accessorIdx]
-	if accessor.Type != gltf.AccessorVec3 {
-		return nil, 0, fmt.Errorf("访问器类型应为VEC3，实际为%s", accessor.Type)
-	}
-
-	if accessor.ComponentType != gltf.ComponentFloat {
-		return nil, 0, fmt.Errorf("TRANSLATION仅支持FLOAT组件类型")
-	}
-
-	data, err := readBufferData(doc, accessor)
+	accessor, err := instanceAccessor(doc, accessorIdx, gltf.AccessorVec3)
 	if err != nil {
 		return nil, 0, err
 	}
-
-	count := accessor.Count
-	result := make([][3]float32, count)
-	for i := uint32(0); i < count; i++ {
-		result[i] = [3]float32{
-			data[3*i],
-			data[3*i+1],
-			data[3*i+2],
-		}
-	}
-
-	return result, count, nil
-}
-
-// 读取四维向量数据 (FLOAT)
-func readVec4Data(doc *gltf.Document, accessorIdx uint32) ([][4]float32, uint32, error) {
-	accessor := doc.Accessors[accessorIdx]
-	if accessor.Type != gltf.AccessorVec4 {
-		return nil, 0, fmt.Errorf("访问器类型应为VEC4，实际为%s", accessor.Type)
-	}
-
-	if accessor.ComponentType != gltf.ComponentFloat {
-		return nil, 0, fmt.Errorf("ROTATION FLOAT类型要求FLOAT组件类型")
-	}
-
-	data, err := readBufferData(doc, accessor)
+	data, err := modeler.ReadAs[[3]float32](doc, accessor, nil)
 	if err != nil {
 		return nil, 0, err
 	}
-
-	count := accessor.Count
-	result := make([][4]float32, count)
-	for i := uint32(0); i < count; i++ {
-		result[i] = [4]float32{
-			data[4*i],
-			data[4*i+1],
-			data[4*i+2],
-			data[4*i+3],
-		}
-	}
-
-	return result, count, nil
+	return data, accessor.Count, nil
 }
 
-// 读取归一化的字节四维向量 (ROTATION)
-func readNormalizedVec4Byte(doc *gltf.Document, accessorIdx uint32) ([][4]float32, uint32, error) {
-	accessor := doc.Accessors[accessorIdx]
-	if accessor.Type != gltf.AccessorVec4 {
-		return nil, 0, fmt.Errorf("访问器类型应为VEC4，实际为%s", accessor.Type)
-	}
-
-	if accessor.ComponentType != gltf.ComponentByte {
-		return nil, 0, fmt.Errorf("应为BYTE组件类型")
-	}
-
-	if !accessor.Normalized {
-		return nil, 0, fmt.Errorf("BYTE ROTATION必须归一化")
-	}
-
-	data, count, err := readByteVec4Data(doc, accessor)
+// 读取四维向量数据 (ROTATION)，归一化的整型分量会被转换为 [-1, 1] 的浮点数
+func readVec4Data(doc *gltf.Document, accessorIdx uint32) ([][4]float32, uint32, error) {
+	accessor, err := instanceAccessor(doc, accessorIdx, gltf.AccessorVec4)
 	if err != nil {
 		return nil, 0, err
 	}
-
-	result := make([][4]float32, count)
-	for i := uint32(0); i < count; i++ {
-		// 将归一化的字节转换为浮点数 [-1, 1]
-		result[i] = [4]float32{
-			float32(data[4*i]) / 127.0,
-			float32(data[4*i+1]) / 127.0,
-			float32(data[4*i+2]) / 127.0,
-			float32(data[4*i+3]) / 127.0,
+	switch accessor.ComponentType {
+	case gltf.ComponentFloat:
+	case gltf.ComponentByte, gltf.ComponentShort:
+		if !accessor.Normalized {
+			return nil, 0, fmt.Errorf("%s ROTATION必须归一化", accessor.ComponentType)
 		}
+	default:
+		return nil, 0, fmt.Errorf("不支持的ROTATION组件类型: %v", accessor.ComponentType)
 	}
-
-	return result, count, nil
-}
-
-// 读取归一化的短整型四维向量 (ROTATION)
-func readNormalizedVec4Short(doc *gltf.Document, accessorIdx uint32) ([][4]float32, uint32, error) {
-	accessor := doc.Accessors[accessorIdx]
-	if accessor.Type != gltf.AccessorVec4 {
-		return nil, 0, fmt.Errorf("访问器类型应为VEC4，实际为%s", accessor.Type)
-	}
-
-	if accessor.ComponentType != gltf.ComponentShort {
-		return nil, 0, fmt.Errorf("应为SHORT组件类型")
-	}
-
-	if !accessor.Normalized {
-		return nil, 0, fmt.Errorf("SHORT ROTATION必须归一化")
-	}
-
-	data, count, err := readShortVec4Data(doc, accessor)
+	data, err := modeler.ReadAs[[4]float32](doc, accessor, nil)
 	if err != nil {
 		return nil, 0, err
 	}
-
-	result := make([][4]float32, count)
-	for i := uint32(0); i < count; i++ {
-		// 将归一化的短整型转换为浮点数 [-1, 1]
-		result[i] = [4]float32{
-			float32(data[4*i]) / 32767.0,
-			float32(data[4*i+1]) / 32767.0,
-			float32(data[4*i+2]) / 32767.0,
-			float32(data[4*i+3]) / 32767.0,
-		}
-	}
-
-	return result, count, nil
+	return data, accessor.Count, nil
 }
 
-// 读取字节向量数据
-func readByteVec4Data(doc *gltf.Document, accessor *gltf.Accessor) ([]int8, uint32, error) {
-	bufferView := doc.BufferViews[*accessor.BufferView]
-	buffer := doc.Buffers[bufferView.Buffer]
-
-	offset := bufferView.ByteOffset + accessor.ByteOffset
-	byteData := buffer.Data[offset : offset+bufferView.ByteLength]
-
-	count := accessor.Count
-	result := make([]int8, count*4)
-	if err := binary.Read(bytes.NewReader(byteData), binary.LittleEndian, &result); err != nil {
-		return nil, 0, fmt.Errorf("二进制读取失败: %w", err)
+// 获取并校验实例属性访问器
+func instanceAccessor(doc *gltf.Document, accessorIdx uint32, tp gltf.AccessorType) (*gltf.Accessor, error) {
+	if int(accessorIdx) >= len(doc.Accessors) {
+		return nil, fmt.Errorf("访问器索引超出范围: %d", accessorIdx)
 	}
-
-	return result, count, nil
-}
-
-// 读取短整型向量数据
-func readShortVec4Data(doc *gltf.Document, accessor *gltf.Accessor) ([]int16, uint32, error) {
-	bufferView := doc.BufferViews[*accessor.BufferView]
-	buffer := doc.Buffers[bufferView.Buffer]
-
-	offset := bufferView.ByteOffset + accessor.ByteOffset
-	byteData := buffer.Data[offset : offset+bufferView.ByteLength]
-
-	count := accessor.Count
-	result := make([]int16, count*4)
-	if err := binary.Read(bytes.NewReader(byteData), binary.LittleEndian, &result); err != nil {
-		return nil, 0, fmt.Errorf("二进制读取失败: %w", err)
+	accessor := doc.Accessors[accessorIdx]
+	if accessor.Type != tp {
+		return nil, fmt.Errorf("访问器类型应为%s，实际为%s", tp, accessor.Type)
 	}
-
-	return result, count, nil
-}
-
-// 从缓冲区读取浮点数据
-func readBufferData(doc *gltf.Document, accessor *gltf.Accessor) ([]float32, error) {
-	bufferView := doc.BufferViews[*accessor.BufferView]
-	buffer := doc.Buffers[bufferView.Buffer]
-
-	offset := bufferView.ByteOffset + accessor.ByteOffset
-	byteData := buffer.Data[offset : offset+bufferView.ByteLength]
-
-	count := accessor.Count
-	var dim uint32
-	switch accessor.Type {
-	case gltf.AccessorVec3:
-		dim = 3
-	case gltf.AccessorVec4:
-		dim = 4
-	default:
-		return nil, fmt.Errorf("不支持的访问器类型: %s", accessor.Type)
-	}
-
-	result := make([]float32, count*dim)
-	if err := binary.Read(bytes.NewReader(byteData), binary.LittleEndian, &result); err != nil {
-		return nil, fmt.Errorf("二进制读取失败: %w", err)
-	}
-
-	return result, nil
+	return accessor, nil
 }
 
 // 创建向量访问器
@@ -598,6 +462,9 @@ func addBufferView(doc *gltf.Document, data []byte) (uint32, error) {
 }
 
 // SetInstanceExtension sets the EXT_mesh_gpu_instancing extension on a node
+//
+// Deprecated: the extension is stored as encoded JSON, which Prune and Merge can't remap.
+// Use WriteInstanceExtension, which also records the extension in the document's extensionsUsed.
 func SetInstanceExtension(node *gltf.Node, attributes map[string]uint32) error {
 	if node.Extensions == nil {
 		node.Extensions = make(gltf.Extensions)
@@ -618,35 +485,19 @@ func SetInstanceExtension(node *gltf.Node, attributes map[string]uint32) error {
 	return nil
 }
 
-// GetInstanceExtension gets the EXT_mesh_gpu_instancing extension from a node
-func GetInstanceExtension(node *gltf.Node) (*InstanceAttributes, error) {
-	if node.Extensions == nil {
-		return nil, fmt.Errorf("no extensions found")
-	}
-
-	extData, exists := node.Extensions[ExtensionName]
-	if !exists {
-		return nil, fmt.Errorf("%s extension not found", ExtensionName)
-	}
-
-	// Type assertion
-	extDataBytes, ok := extData.([]byte)
-	if !ok {
-		return nil, fmt.Errorf("extension data is not in expected format ([]byte)")
-	}
-
-	// Unmarshal the extension data
-	ext, err := Unmarshal(extDataBytes)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling EXT_mesh_gpu_instancing extension: %w", err)
-	}
-
-	attrs, ok := ext.(*InstanceAttributes)
-	if !ok {
-		return nil, fmt.Errorf("extension data is not of expected type (*InstanceAttributes)")
+// WriteInstanceExtension sets the EXT_mesh_gpu_instancing extension on a node
+// and adds it to the extensions used by doc.
+func WriteInstanceExtension(doc *gltf.Document, node *gltf.Node, attributes map[string]uint32) error {
+	if len(attributes) == 0 {
+		return fmt.Errorf("at least one attribute is required")
 	}
+	gltf.SetExtension(doc, &node.Extensions, ExtensionName, &InstanceAttributes{Attributes: attributes})
+	return nil
+}
 
-	return attrs, nil
+// GetInstanceExtension gets the EXT_mesh_gpu_instancing extension from a node
+func GetInstanceExtension(node *gltf.Node) (*InstanceAttributes, error) {
+	return gltf.GetExtension[*InstanceAttributes](node.Extensions, ExtensionName)
 }
 
 // ValidateInstanceAttributes validates that the instance attributes conform to the specification
@@ -1,9 +1,11 @@
 package instance
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/flywave/gltf"
+	"github.com/flywave/gltf/modeler"
 )
 
 func TestUnmarshal(t *testing.T) {
@@ -61,6 +63,16 @@ func TestUnmarshal(t *testing.T) {
 	_ = ext
 }
 
+func TestUnmarshal_anyScope(t *testing.T) {
+	var ext gltf.Extensions
+	if err := json.Unmarshal([]byte(`{"EXT_mesh_gpu_instancing": {"attributes": {"TRANSLATION": 0}}}`), &ext); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if attrs, ok := ext[ExtensionName].(*InstanceAttributes); !ok || attrs.Attributes["TRANSLATION"] != 0 {
+		t.Errorf("Extensions[%s] = %#v", ExtensionName, ext[ExtensionName])
+	}
+}
+
 func TestInstanceData_InstanceCount(t *testing.T) {
 	data := &InstanceData{
 		Translations: [][3]float32{{1, 2, 3}, {4, 5, 6}},
@@ -171,6 +183,49 @@ func TestSetInstanceExtension(t *testing.T) {
 	}
 }
 
+func TestWriteInstanceExtension(t *testing.T) {
+	// Create a node
+	node := &gltf.Node{
+		Extensions: make(gltf.Extensions),
+	}
+
+	// Set instance extension
+	attributes := map[string]uint32{
+		"TRANSLATION": 0,
+		"ROTATION":    1,
+		"SCALE":       2,
+	}
+
+	doc := gltf.NewDocument()
+	err := WriteInstanceExtension(doc, node, attributes)
+	if err != nil {
+		t.Errorf("WriteInstanceExtension failed: %v", err)
+	}
+	if !doc.HasExtensionUsed(ExtensionName) {
+		t.Error("Instance extension was not added to extensionsUsed")
+	}
+
+	// Check that the extension was set
+	extData, exists := node.Extensions[ExtensionName]
+	if !exists {
+		t.Fatal("Instance extension was not set on the node")
+	}
+
+	// Check that the extension data is in the correct format
+	attrs, ok := extData.(*InstanceAttributes)
+	if !ok {
+		t.Fatal("Extension data is not of type *InstanceAttributes")
+	}
+
+	if len(attrs.Attributes) != 3 {
+		t.Errorf("Expected 3 attributes, got %d", len(attrs.Attributes))
+	}
+
+	if err := WriteInstanceExtension(doc, node, nil); err == nil {
+		t.Error("WriteInstanceExtension should have failed without attributes")
+	}
+}
+
 func TestGetInstanceExtension(t *testing.T) {
 	// Create a node with instance extension
 	node := &gltf.Node{
@@ -293,3 +348,30 @@ func TestValidateInstanceAttributes(t *testing.T) {
 		t.Error("ValidateInstanceAttributes should have failed with invalid custom attribute name")
 	}
 }
+
+func TestReadInstancing_Quantized(t *testing.T) {
+	doc := gltf.NewDocument()
+	translations := modeler.WriteAccessor(doc, gltf.TargetNone, [][3]float32{{1, 2, 3}, {4, 5, 6}})
+	rotations := modeler.WriteAccessor(doc, gltf.TargetNone, [][4]int16{{0, 0, 0, 32767}, {-32767, 0, 0, 0}})
+	doc.Accessors[rotations].Normalized = true
+	doc.Nodes = []*gltf.Node{{}}
+	if err := WriteInstanceExtension(doc, doc.Nodes[0], map[string]uint32{"TRANSLATION": translations, "ROTATION": rotations}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ReadInstancing(doc, 0)
+	if err != nil {
+		t.Fatalf("ReadInstancing failed: %v", err)
+	}
+	if data.Rotations[0] != [4]float32{0, 0, 0, 1} || data.Rotations[1] != [4]float32{-1, 0, 0, 0} {
+		t.Errorf("Unexpected rotations: %v", data.Rotations)
+	}
+	if data.Translations[1] != [3]float32{4, 5, 6} {
+		t.Errorf("Unexpected translations: %v", data.Translations)
+	}
+
+	doc.Accessors[rotations].Normalized = false
+	if _, err := ReadInstancing(doc, 0); err == nil {
+		t.Error("ReadInstancing should have failed with non normalized short rotations")
+	}
+}
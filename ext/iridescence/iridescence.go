@@ -33,6 +33,13 @@ type MaterialsIridescence struct {
 	IridescenceThicknessTexture *gltf.TextureInfo `json:"iridescenceThicknessTexture,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture indices rewritten by remap.
+func (m MaterialsIridescence) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	m.IridescenceTexture = gltf.RemapTextureInfo(remap, m.IridescenceTexture)
+	m.IridescenceThicknessTexture = gltf.RemapTextureInfo(remap, m.IridescenceThicknessTexture)
+	return m
+}
+
 // UnmarshalJSON unmarshal the iridescence material with the correct default values.
 func (m *MaterialsIridescence) UnmarshalJSON(data []byte) error {
 	type alias MaterialsIridescence
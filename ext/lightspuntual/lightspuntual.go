@@ -5,6 +5,8 @@ package lightspuntual
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 
 	"github.com/flywave/gltf"
@@ -13,10 +15,14 @@ import (
 const (
 	// ExtensionName defines the KHR_lights_punctual unique key.
 	ExtensionName = "KHR_lights_punctual"
+	// LightsProperty is the name of the lights array in gltf.IndexRemapFunc.
+	LightsProperty = ExtensionName + ".lights"
 )
 
 func init() {
 	gltf.RegisterExtension(ExtensionName, Unmarshal)
+	gltf.RegisterScopedExtension(ExtensionName, gltf.ScopeDocument, UnmarshalLights)
+	gltf.RegisterScopedExtension(ExtensionName, gltf.ScopeNode, UnmarshalLightIndex)
 }
 
 type envelop struct {
@@ -36,6 +42,29 @@ func Unmarshal(data []byte) (interface{}, error) {
 	return env.Lights, nil
 }
 
+// UnmarshalLights decodes the json data of the document extension.
+func UnmarshalLights(data []byte) (interface{}, error) {
+	var env struct {
+		Lights Lights `json:"lights"`
+	}
+	err := json.Unmarshal(data, &env)
+	return env.Lights, err
+}
+
+// UnmarshalLightIndex decodes the json data of a node extension.
+func UnmarshalLightIndex(data []byte) (interface{}, error) {
+	var env struct {
+		Light *LightIndex `json:"light"`
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.Light == nil {
+		return nil, errors.New("gltf: KHR_lights_punctual node extension requires a light")
+	}
+	return *env.Light, nil
+}
+
 const (
 	// TypeDirectional lights act as though they are infinitely far away and emit light in the direction of the local -z axis.
 	TypeDirectional = "directional"
@@ -48,6 +77,11 @@ const (
 // LightIndex is the id of the light referenced by this node.
 type LightIndex uint32
 
+// RemapIndices returns the light index rewritten by remap.
+func (l LightIndex) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	return LightIndex(remap(LightsProperty, uint32(l)))
+}
+
 // Spot defines the spot cone.
 type Spot struct {
 	InnerConeAngle float32  `json:"innerConeAngle,omitempty"`
@@ -76,6 +110,17 @@ func (s *Spot) UnmarshalJSON(data []byte) error {
 // Lights defines a list of Lights.
 type Lights []*Light
 
+// MergeExtension appends the lights of other, which must be Lights, to a copy of l.
+func (l Lights) MergeExtension(other interface{}) (interface{}, map[string]uint32, error) {
+	o, ok := other.(Lights)
+	if !ok {
+		return nil, nil, fmt.Errorf("gltf: cannot merge %s with %T", ExtensionName, other)
+	}
+	merged := make(Lights, 0, len(l)+len(o))
+	merged = append(append(merged, l...), o...)
+	return merged, map[string]uint32{LightsProperty: uint32(len(l))}, nil
+}
+
 // Light defines a directional, point, or spot light.
 // When a light's type is spot, the spot property on the light is required.
 type Light struct {
@@ -1,6 +1,7 @@
 package lightspuntual
 
 import (
+	"encoding/json"
 	"math"
 	"reflect"
 	"testing"
@@ -150,3 +151,49 @@ func TestUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestDecode_scoped(t *testing.T) {
+	data := []byte(`{
+		"asset": {"version": "2.0"},
+		"extensions": {"KHR_lights_punctual": {"lights": [{"type": "point"}]}},
+		"nodes": [{"extensions": {"KHR_lights_punctual": {"light": 0}}}]
+	}`)
+	var doc gltf.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := Lights{{Type: "point", Color: &[3]float32{1, 1, 1}, Intensity: gltf.Float(1), Range: gltf.Float(float32(math.Inf(0)))}}
+	if diff := deep.Equal(doc.Extensions[ExtensionName], want); diff != nil {
+		t.Errorf("Document.Extensions = %v", diff)
+	}
+	if got := doc.Nodes[0].Extensions[ExtensionName]; got != LightIndex(0) {
+		t.Errorf("Node.Extensions = %v, want %v", got, LightIndex(0))
+	}
+}
+
+func TestUnmarshalLightIndex(t *testing.T) {
+	if _, err := UnmarshalLightIndex([]byte(`{"lights": []}`)); err == nil {
+		t.Error("UnmarshalLightIndex() expected error")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dst := &gltf.Document{
+		Extensions: gltf.Extensions{ExtensionName: Lights{{Type: TypePoint, Name: "a"}}},
+		Nodes:      []*gltf.Node{{Extensions: gltf.Extensions{ExtensionName: LightIndex(0)}}},
+	}
+	src := &gltf.Document{
+		Extensions: gltf.Extensions{ExtensionName: Lights{{Type: TypeSpot, Name: "b"}, {Type: TypeDirectional, Name: "c"}}},
+		Nodes:      []*gltf.Node{{Extensions: gltf.Extensions{ExtensionName: LightIndex(1)}}},
+	}
+	if err := gltf.Merge(dst, src); err != nil {
+		t.Fatalf("gltf.Merge() error = %v", err)
+	}
+	want := Lights{{Type: TypePoint, Name: "a"}, {Type: TypeSpot, Name: "b"}, {Type: TypeDirectional, Name: "c"}}
+	if diff := deep.Equal(dst.Extensions[ExtensionName], want); diff != nil {
+		t.Errorf("gltf.Merge() lights = %v", diff)
+	}
+	if got := dst.Nodes[1].Extensions[ExtensionName]; got != LightIndex(2) {
+		t.Errorf("gltf.Merge() light index = %v, want %v", got, LightIndex(2))
+	}
+}
@@ -40,6 +40,12 @@ type CompressionExtension struct {
 	Filter     CompressionFilter `json:"filter,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the buffer index rewritten by remap.
+func (e CompressionExtension) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	e.Buffer = remap(gltf.PropertyBuffers, e.Buffer)
+	return e
+}
+
 func init() {
 	gltf.RegisterExtension(ExtensionName, Unmarshal)
 }
@@ -31,6 +31,13 @@ type MaterialsSheen struct {
 	SheenRoughnessTexture *gltf.TextureInfo `json:"sheenRoughnessTexture,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture indices rewritten by remap.
+func (m MaterialsSheen) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	m.SheenColorTexture = gltf.RemapTextureInfo(remap, m.SheenColorTexture)
+	m.SheenRoughnessTexture = gltf.RemapTextureInfo(remap, m.SheenRoughnessTexture)
+	return m
+}
+
 // UnmarshalJSON unmarshal the sheen material with the correct default values.
 func (m *MaterialsSheen) UnmarshalJSON(data []byte) error {
 	type alias MaterialsSheen
@@ -31,6 +31,13 @@ type PBRSpecularGlossiness struct {
 	SpecularGlossinessTexture *gltf.TextureInfo `json:"specularGlossinessTexture,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture indices rewritten by remap.
+func (p PBRSpecularGlossiness) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	p.DiffuseTexture = gltf.RemapTextureInfo(remap, p.DiffuseTexture)
+	p.SpecularGlossinessTexture = gltf.RemapTextureInfo(remap, p.SpecularGlossinessTexture)
+	return p
+}
+
 // UnmarshalJSON unmarshal the pbr with the correct default values.
 func (p *PBRSpecularGlossiness) UnmarshalJSON(data []byte) error {
 	type alias PBRSpecularGlossiness
@@ -31,6 +31,13 @@ type MaterialsSpecular struct {
 	SpecularColorTexture *gltf.TextureInfo `json:"specularColorTexture,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture indices rewritten by remap.
+func (m MaterialsSpecular) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	m.SpecularTexture = gltf.RemapTextureInfo(remap, m.SpecularTexture)
+	m.SpecularColorTexture = gltf.RemapTextureInfo(remap, m.SpecularColorTexture)
+	return m
+}
+
 // UnmarshalJSON unmarshal the specular material with the correct default values.
 func (m *MaterialsSpecular) UnmarshalJSON(data []byte) error {
 	type alias MaterialsSpecular
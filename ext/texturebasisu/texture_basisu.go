@@ -29,6 +29,12 @@ func UnmarshalExtTextureBasisu(data []byte) (interface{}, error) {
 	return &ext, nil
 }
 
+// RemapIndices returns a copy of the extension with the image index rewritten by remap.
+func (e ExtTextureBasisu) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	e.Source = remap(gltf.PropertyImages, e.Source)
+	return e
+}
+
 // SetSource sets the source image index
 func (e *ExtTextureBasisu) SetSource(source uint32) {
 	e.Source = source
@@ -29,6 +29,12 @@ type MaterialsTransmission struct {
 	TransmissionTexture *gltf.TextureInfo `json:"transmissionTexture,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture indices rewritten by remap.
+func (m MaterialsTransmission) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	m.TransmissionTexture = gltf.RemapTextureInfo(remap, m.TransmissionTexture)
+	return m
+}
+
 // UnmarshalJSON unmarshal the transmission material with the correct default values.
 func (m *MaterialsTransmission) UnmarshalJSON(data []byte) error {
 	type alias MaterialsTransmission
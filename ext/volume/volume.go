@@ -31,6 +31,12 @@ type MaterialsVolume struct {
 	AttenuationColor    *[3]float32       `json:"attenuationColor,omitempty"`
 }
 
+// RemapIndices returns a copy of the extension with the texture indices rewritten by remap.
+func (m MaterialsVolume) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	m.ThicknessTexture = gltf.RemapTextureInfo(remap, m.ThicknessTexture)
+	return m
+}
+
 // UnmarshalJSON unmarshal the volume material with the correct default values.
 func (m *MaterialsVolume) UnmarshalJSON(data []byte) error {
 	type alias MaterialsVolume
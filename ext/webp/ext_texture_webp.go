@@ -32,6 +32,12 @@ func UnmarshalExtTextureWebp(data []byte) (interface{}, error) {
 	return &ext, nil
 }
 
+// RemapIndices returns a copy of the extension with the image index rewritten by remap.
+func (e ExtTextureWebp) RemapIndices(remap gltf.IndexRemapFunc) interface{} {
+	e.Source = gltf.RemapIndex(remap, gltf.PropertyImages, e.Source)
+	return e
+}
+
 // SetSource sets the source image index
 func (e *ExtTextureWebp) SetSource(source uint32) {
 	e.Source = &source
@@ -0,0 +1,163 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// ErrExtensionNotFound is returned by GetExtension when the extension is not present.
+var ErrExtensionNotFound = errors.New("gltf: extension not found")
+
+// GetExtension returns the payload of the extension name stored in ext as a T,
+// which is usually a pointer to the struct defined by the extension package.
+// A payload stored as a value and asked for as a pointer is replaced in ext by a pointer
+// to a copy of it, so that changes made through the returned pointer are kept.
+// A payload stored as a pointer and asked for as a value is returned as a copy.
+//
+// Payloads that have not been decoded yet, either a json.RawMessage or a []byte,
+// are decoded with the factory registered for name, or with json.Unmarshal
+// if there is none or it doesn't return a T, and the decoded payload replaces
+// the raw one in ext.
+func GetExtension[T any](ext Extensions, name string) (T, error) {
+	var zero T
+	v, ok := ext[name]
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrExtensionNotFound, name)
+	}
+	if t, ok := extensionAs[T](v); ok {
+		storeExtension(ext, name, v, t)
+		return t, nil
+	}
+	var raw []byte
+	switch v := v.(type) {
+	case json.RawMessage:
+		raw = v
+	case []byte:
+		raw = v
+	default:
+		return zero, fmt.Errorf("gltf: extension %s is a %T, not a %T", name, v, zero)
+	}
+	for _, f := range extensionFactories(name) {
+		if d, err := f(raw); err == nil {
+			if t, ok := extensionAs[T](d); ok {
+				storeExtension(ext, name, d, t)
+				return t, nil
+			}
+		}
+	}
+	var t T
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return zero, fmt.Errorf("gltf: decoding extension %s: %w", name, err)
+	}
+	ext[name] = t
+	return t, nil
+}
+
+// SetExtension stores payload as the extension name of *ext, allocating the map if needed,
+// and adds name to doc.ExtensionsUsed.
+//
+// If payload is nil the extension is removed from *ext instead, and name is removed from
+// doc.ExtensionsUsed and doc.ExtensionsRequired when no other object of doc uses it.
+func SetExtension(doc *Document, ext *Extensions, name string, payload interface{}) {
+	if payload == nil {
+		delete(*ext, name)
+		if !doc.usesExtension(name) {
+			doc.ExtensionsUsed = slices.DeleteFunc(doc.ExtensionsUsed, func(s string) bool { return s == name })
+			doc.ExtensionsRequired = slices.DeleteFunc(doc.ExtensionsRequired, func(s string) bool { return s == name })
+		}
+		return
+	}
+	if *ext == nil {
+		*ext = make(Extensions)
+	}
+	(*ext)[name] = payload
+	doc.AddExtensionUsed(name)
+}
+
+// extensionAs returns v as a T, converting between values and pointers to values.
+func extensionAs[T any](v interface{}) (T, bool) {
+	if t, ok := v.(T); ok {
+		return t, true
+	}
+	var zero T
+	rv := reflect.ValueOf(v)
+	tt := reflect.TypeOf((*T)(nil)).Elem()
+	switch {
+	case !rv.IsValid():
+	case rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Type().Elem() == tt:
+		return rv.Elem().Interface().(T), true
+	case tt.Kind() == reflect.Pointer && tt.Elem() == rv.Type():
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		return p.Interface().(T), true
+	}
+	return zero, false
+}
+
+// storeExtension stores the payload v as the extension name of ext,
+// or t instead when it is a pointer to a copy of v.
+func storeExtension[T any](ext Extensions, name string, v interface{}, t T) {
+	if tt := reflect.TypeOf(t); tt != reflect.TypeOf(v) && tt.Kind() == reflect.Pointer {
+		ext[name] = t
+	} else {
+		ext[name] = v
+	}
+}
+
+// extensionFactories returns the factories registered for name,
+// the one registered for ScopeAny first.
+func extensionFactories(name string) []func([]byte) (interface{}, error) {
+	extMu.RLock()
+	defer extMu.RUnlock()
+	var scopes []ExtensionScope
+	for k := range extensions {
+		if k.name == name {
+			scopes = append(scopes, k.scope)
+		}
+	}
+	sort.Slice(scopes, func(i, j int) bool { return scopes[i] < scopes[j] })
+	factories := make([]func([]byte) (interface{}, error), len(scopes))
+	for i, scope := range scopes {
+		factories[i] = extensions[extensionKey{name, scope}]
+	}
+	return factories
+}
+
+// usesExtension reports whether any object of doc has the extension name,
+// including the objects nested in extension payloads.
+// Payloads that were not decoded are assumed to use it if their JSON contains its name.
+func (doc *Document) usesExtension(name string) bool {
+	found := false
+	quoted := []byte(strconv.Quote(name))
+	w := &indexRewriter{remap: func(_ string, index uint32) uint32 { return index }, dryRun: true}
+	w.visit = func(ext Extensions) {
+		for n, v := range ext {
+			if n == name {
+				found = true
+				return
+			}
+			if opaquePayload(v) {
+				var raw []byte
+				switch v := v.(type) {
+				case json.RawMessage:
+					raw = v
+				case []byte:
+					raw = v
+				default:
+					raw, _ = json.Marshal(v)
+				}
+				found = found || bytes.Contains(raw, quoted)
+			}
+		}
+	}
+	w.document(doc)
+	w.extensions(doc.Asset.Extensions)
+	w.extensions(doc.Extensions)
+	return found
+}
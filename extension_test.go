@@ -0,0 +1,132 @@
+package gltf
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type testExtension struct {
+	Value int `json:"value"`
+}
+
+func init() {
+	RegisterExtension("TEST_get_extension", func(data []byte) (interface{}, error) {
+		ext := new(testExtension)
+		return ext, json.Unmarshal(data, ext)
+	})
+}
+
+func TestGetExtension(t *testing.T) {
+	tests := []struct {
+		name    string
+		ext     Extensions
+		key     string
+		want    *testExtension
+		stored  interface{}
+		wantErr bool
+	}{
+		{"pointer", Extensions{"a": &testExtension{1}}, "a", &testExtension{1}, &testExtension{1}, false},
+		{"value", Extensions{"a": testExtension{2}}, "a", &testExtension{2}, &testExtension{2}, false},
+		{"raw registered", Extensions{"TEST_get_extension": json.RawMessage(`{"value":3}`)}, "TEST_get_extension", &testExtension{3}, &testExtension{3}, false},
+		{"bytes registered", Extensions{"TEST_get_extension": []byte(`{"value":4}`)}, "TEST_get_extension", &testExtension{4}, &testExtension{4}, false},
+		{"raw unregistered", Extensions{"a": json.RawMessage(`{"value":5}`)}, "a", &testExtension{5}, &testExtension{5}, false},
+		{"invalid raw", Extensions{"a": json.RawMessage(`{"value":"x"}`)}, "a", nil, json.RawMessage(`{"value":"x"}`), true},
+		{"other type", Extensions{"a": "x"}, "a", nil, "x", true},
+		{"missing", Extensions{}, "a", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetExtension[*testExtension](tt.ext, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetExtension() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("GetExtension() = %v", diff)
+			}
+			if diff := deep.Equal(tt.ext[tt.key], tt.stored); diff != nil {
+				t.Errorf("GetExtension() stored = %v", diff)
+			}
+			if p, ok := tt.ext[tt.key].(*testExtension); ok && p != got {
+				t.Error("GetExtension() didn't return the stored pointer")
+			}
+		})
+	}
+}
+
+func TestGetExtension_notFound(t *testing.T) {
+	if _, err := GetExtension[testExtension](nil, "a"); !errors.Is(err, ErrExtensionNotFound) {
+		t.Errorf("GetExtension() error = %v, want ErrExtensionNotFound", err)
+	}
+}
+
+func TestSetExtension(t *testing.T) {
+	doc := &Document{Nodes: []*Node{{}, {}}, ExtensionsRequired: []string{"a"}}
+	SetExtension(doc, &doc.Nodes[0].Extensions, "a", &testExtension{1})
+	SetExtension(doc, &doc.Nodes[1].Extensions, "a", &testExtension{2})
+	SetExtension(doc, &doc.Extensions, "b", &testExtension{3})
+	if diff := deep.Equal(doc.ExtensionsUsed, []string{"a", "b"}); diff != nil {
+		t.Errorf("SetExtension() used = %v", diff)
+	}
+	if diff := deep.Equal(doc.Nodes[0].Extensions, Extensions{"a": &testExtension{1}}); diff != nil {
+		t.Errorf("SetExtension() = %v", diff)
+	}
+
+	SetExtension(doc, &doc.Nodes[0].Extensions, "a", nil)
+	if diff := deep.Equal(doc.ExtensionsUsed, []string{"a", "b"}); diff != nil {
+		t.Errorf("SetExtension() used = %v", diff)
+	}
+	SetExtension(doc, &doc.Nodes[1].Extensions, "a", nil)
+	if diff := deep.Equal(doc.ExtensionsUsed, []string{"b"}); diff != nil {
+		t.Errorf("SetExtension() used = %v", diff)
+	}
+	if len(doc.ExtensionsRequired) != 0 {
+		t.Errorf("SetExtension() required = %v", doc.ExtensionsRequired)
+	}
+	if _, ok := doc.Nodes[1].Extensions["a"]; ok {
+		t.Error("SetExtension() expected extension to be removed")
+	}
+}
+
+func TestSetExtension_nested(t *testing.T) {
+	type clearcoat struct{ Texture *TextureInfo }
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    []string
+	}{
+		{"nested", clearcoat{&TextureInfo{Extensions: Extensions{"a": &testExtension{1}}}}, []string{"a", "c"}},
+		{"raw", json.RawMessage(`{"texture":{"extensions":{"a":{}}}}`), []string{"a", "c"}},
+		{"map", map[string]interface{}{"extensions": map[string]interface{}{"a": true}}, []string{"a", "c"}},
+		{"unused", clearcoat{&TextureInfo{}}, []string{"c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				ExtensionsUsed: []string{"a", "c"},
+				Nodes:          []*Node{{Extensions: Extensions{"a": &testExtension{1}}}, nil},
+				Materials:      []*Material{nil, {Extensions: Extensions{"c": tt.payload}}},
+				Cameras:        []*Camera{{Perspective: &Perspective{}}},
+			}
+			SetExtension(doc, &doc.Nodes[0].Extensions, "a", nil)
+			if diff := deep.Equal(doc.ExtensionsUsed, tt.want); diff != nil {
+				t.Errorf("SetExtension() used = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_AddExtensionRequired(t *testing.T) {
+	doc := &Document{ExtensionsUsed: []string{"a"}}
+	doc.AddExtensionRequired("a")
+	doc.AddExtensionRequired("b")
+	doc.AddExtensionRequired("a")
+	if diff := deep.Equal(doc.ExtensionsUsed, []string{"a", "b"}); diff != nil {
+		t.Errorf("AddExtensionRequired() used = %v", diff)
+	}
+	if diff := deep.Equal(doc.ExtensionsRequired, []string{"a", "b"}); diff != nil {
+		t.Errorf("AddExtensionRequired() required = %v", diff)
+	}
+}
@@ -2,6 +2,7 @@ package gltf
 
 import (
 	"encoding/base64"
+	"io"
 	"strings"
 	"sync"
 )
@@ -83,6 +84,17 @@ func (doc *Document) HasExtensionUsed(name string) bool {
 	return false
 }
 
+// AddExtensionRequired add extension required, which is also used
+func (doc *Document) AddExtensionRequired(name string) {
+	doc.AddExtensionUsed(name)
+	for _, ext := range doc.ExtensionsRequired {
+		if ext == name {
+			return
+		}
+	}
+	doc.ExtensionsRequired = append(doc.ExtensionsRequired, name)
+}
+
 // An Accessor is a typed view into a bufferView.
 // An accessor provides a typed view into a bufferView or a subset of a bufferView
 // similar to how WebGL's vertexAttribPointer() defines an attribute in a buffer.
@@ -137,11 +149,50 @@ type Buffer struct {
 	URI        string      `json:"uri,omitempty" validate:"omitempty"`
 	ByteLength uint32      `json:"byteLength" validate:"required"`
 	Data       []byte      `json:"-"`
+	Source     io.ReaderAt `json:"-"` // Random access to the bytes not loaded into Data.
+}
+
+// ReadRange returns length bytes of the buffer starting at offset.
+// The returned slice shares memory with Data when it is loaded,
+// else the bytes are read from Source.
+func (b *Buffer) ReadRange(offset, length uint32) ([]byte, error) {
+	high := uint64(offset) + uint64(length)
+	if b.Data == nil && b.Source != nil {
+		if uint64(b.ByteLength) < high {
+			return nil, io.ErrShortBuffer
+		}
+		p := make([]byte, length)
+		n, err := b.Source.ReadAt(p, int64(offset))
+		if n == len(p) {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	if uint64(len(b.Data)) < high {
+		return nil, io.ErrShortBuffer
+	}
+	return b.Data[offset:high], nil
+}
+
+// Load reads the whole buffer from Source into Data.
+// It does nothing if Data is already loaded or if there is no Source.
+func (b *Buffer) Load() error {
+	if b.Data != nil || b.Source == nil {
+		return nil
+	}
+	data, err := b.ReadRange(0, b.ByteLength)
+	if err == nil {
+		b.Data = data
+	}
+	return err
 }
 
 // IsEmbeddedResource returns true if the buffer points to an embedded resource.
 func (b *Buffer) IsEmbeddedResource() bool {
-	return strings.HasPrefix(b.URI, mimetypeApplicationOctet)
+	return strings.HasPrefix(b.URI, dataURIPrefix)
 }
 
 // EmbeddedResource defines the buffer as an embedded resource and encodes the URI so it points to the the resource.
@@ -154,8 +205,7 @@ func (b *Buffer) MarshalData() ([]byte, error) {
 	if !b.IsEmbeddedResource() {
 		return nil, nil
 	}
-	startPos := len(mimetypeApplicationOctet) + 1
-	sl, err := base64.StdEncoding.DecodeString(b.URI[startPos:])
+	_, sl, err := parseDataURI(b.URI)
 	if len(sl) == 0 || err != nil {
 		return nil, err
 	}
@@ -227,6 +277,34 @@ func (n *Node) TranslationOrDefault() [3]float32 {
 	return n.Translation
 }
 
+// LocalMatrix returns the transform of the node relative to its parent,
+// which is Matrix if defined, else the composition of Translation, Rotation and Scale.
+func (n *Node) LocalMatrix() [16]float32 {
+	if m := n.MatrixOrDefault(); m != DefaultMatrix {
+		return m
+	}
+	return ComposeMatrix(n.TranslationOrDefault(), n.RotationOrDefault(), n.ScaleOrDefault())
+}
+
+// UseMatrix converts the node transform to the Matrix form,
+// resetting Translation, Rotation and Scale to their defaults.
+func (n *Node) UseMatrix() {
+	n.Matrix = n.LocalMatrix()
+	n.Translation, n.Rotation, n.Scale = DefaultTranslation, DefaultRotation, DefaultScale
+}
+
+// UseTRS converts the node transform to the Translation, Rotation and Scale form,
+// resetting Matrix to the identity. Animation channels can only target this form.
+// It returns ErrNotDecomposable, leaving the node unchanged, if Matrix has shear or projective terms.
+func (n *Node) UseTRS() error {
+	t, r, s, err := DecomposeMatrix(n.LocalMatrix())
+	if err != nil {
+		return err
+	}
+	n.Matrix, n.Translation, n.Rotation, n.Scale = DefaultMatrix, t, r, s
+	return nil
+}
+
 // Skin defines joints and matrices.
 type Skin struct {
 	Extensions          Extensions  `json:"extensions,omitempty"`
@@ -413,13 +491,37 @@ type Image struct {
 	Extras     interface{} `json:"extras,omitempty"`
 	Name       string      `json:"name,omitempty"`
 	URI        string      `json:"uri,omitempty" validate:"omitempty"`
-	MimeType   string      `json:"mimeType,omitempty" validate:"omitempty,oneof=image/jpeg image/png"` // Manadatory if BufferView is defined.
-	BufferView *uint32     `json:"bufferView,omitempty"`                                               // Use this instead of the image's uri property.
+	MimeType   string      `json:"mimeType,omitempty" validate:"omitempty,oneof=image/jpeg image/png image/webp image/ktx2 image/avif image/gif"` // Manadatory if BufferView is defined.
+	BufferView *uint32     `json:"bufferView,omitempty"`                                                                                          // Use this instead of the image's uri property.
+	Data       []byte      `json:"-"`                                                                                                             // Content of the external resource referenced by URI, if loaded.
 }
 
-// IsEmbeddedResource returns true if the buffer points to an embedded resource.
+// IsEmbeddedResource returns true if the image points to an embedded resource.
 func (im *Image) IsEmbeddedResource() bool {
-	return strings.HasPrefix(im.URI, mimetypeImagePNG) || strings.HasPrefix(im.URI, mimetypeImageJPG)
+	return strings.HasPrefix(im.URI, dataURIPrefix)
+}
+
+// EmbeddedResource defines the image as an embedded resource and encodes the URI so it points to the the resource.
+// The media type of the URI is MimeType or, if empty, the one detected from Data.
+func (im *Image) EmbeddedResource() {
+	mimeType := im.MimeType
+	if mimeType == "" {
+		mimeType = DetectImageMimeType(im.Data)
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	im.URI = dataURIPrefix + mimeType + ";base64," + base64.StdEncoding.EncodeToString(im.Data)
+}
+
+// EmbeddedMimeType returns the media type declared by the data URI of an embedded image.
+func (im *Image) EmbeddedMimeType() string {
+	if !im.IsEmbeddedResource() {
+		return ""
+	}
+	header, _, _ := strings.Cut(im.URI[len(dataURIPrefix):], ",")
+	mimeType, _, _ := strings.Cut(header, ";")
+	return strings.ToLower(strings.TrimSpace(mimeType))
 }
 
 // MarshalData decode the image from the URI. If the image is not en embedded resource the returned array will be empty.
@@ -427,12 +529,11 @@ func (im *Image) MarshalData() ([]byte, error) {
 	if !im.IsEmbeddedResource() {
 		return []byte{}, nil
 	}
-	mimetype := mimetypeImagePNG
-	if strings.HasPrefix(im.URI, mimetypeImageJPG) {
-		mimetype = mimetypeImageJPG
+	_, data, err := parseDataURI(im.URI)
+	if data == nil {
+		data = []byte{}
 	}
-	startPos := len(mimetype) + 1
-	return base64.StdEncoding.DecodeString(im.URI[startPos:])
+	return data, err
 }
 
 // An Animation keyframe.
@@ -478,24 +579,81 @@ type ChannelTarget struct {
 // If a key does not match with any of the supported extensions the value will be a json.RawMessage so its decoding can be delayed.
 type Extensions map[string]interface{}
 
+// ExtensionScope identifies the kind of glTF object that owns an Extensions map.
+// Extensions whose payload depends on where they appear, such as KHR_lights_punctual,
+// can register a different factory for each scope.
+type ExtensionScope uint8
+
+const (
+	ScopeAny ExtensionScope = iota // Matches any owner, used as fallback.
+	ScopeDocument
+	ScopeAsset
+	ScopeAccessor
+	ScopeAnimation
+	ScopeBuffer
+	ScopeBufferView
+	ScopeCamera
+	ScopeImage
+	ScopeMaterial
+	ScopeMesh
+	ScopeNode
+	ScopePrimitive
+	ScopeSampler
+	ScopeScene
+	ScopeSkin
+	ScopeTexture
+	ScopeTextureInfo // TextureInfo, NormalTexture and OcclusionTexture.
+)
+
+type extensionKey struct {
+	name  string
+	scope ExtensionScope
+}
+
 var (
 	extMu      sync.RWMutex
-	extensions = make(map[string]func([]byte) (interface{}, error))
+	extensions = make(map[extensionKey]func([]byte) (interface{}, error))
 )
 
 // RegisterExtension registers a function that returns a new extension of the given
 // byte array. This is intended to be called from the init function in
 // packages that implement extensions.
+//
+// The function is used regardless of the object that owns the extension
+// unless a more specific one has been registered with RegisterScopedExtension.
 func RegisterExtension(key string, f func([]byte) (interface{}, error)) {
+	RegisterScopedExtension(key, ScopeAny, f)
+}
+
+// RegisterScopedExtension registers a function that returns a new extension of the given
+// byte array when it is found in an object of the given scope.
+// This is intended to be called from the init function in
+// packages that implement extensions.
+func RegisterScopedExtension(key string, scope ExtensionScope, f func([]byte) (interface{}, error)) {
 	extMu.Lock()
 	defer extMu.Unlock()
-	extensions[key] = f
+	extensions[extensionKey{key, scope}] = f
 }
 
+// queryExtension reports whether key has been registered in any scope.
+// It returns the factory registered for the lowest scope, ScopeAny first,
+// so the result does not depend on the map iteration order.
 func queryExtension(key string) (func([]byte) (interface{}, error), bool) {
+	if factories := extensionFactories(key); len(factories) > 0 {
+		return factories[0], true
+	}
+	return nil, false
+}
+
+// queryScopedExtension returns the factory registered for key and scope,
+// falling back to the one registered for any scope.
+func queryScopedExtension(key string, scope ExtensionScope) (func([]byte) (interface{}, error), bool) {
 	extMu.RLock()
-	ext, ok := extensions[key]
-	extMu.RUnlock()
+	defer extMu.RUnlock()
+	if ext, ok := extensions[extensionKey{key, scope}]; ok {
+		return ext, ok
+	}
+	ext, ok := extensions[extensionKey{key, ScopeAny}]
 	return ext, ok
 }
 
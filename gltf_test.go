@@ -3,6 +3,7 @@ package gltf
 import (
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -36,6 +37,7 @@ func TestBuffer_IsEmbeddedResource(t *testing.T) {
 		want bool
 	}{
 		{"embedded", &Buffer{URI: "data:application/octet-stream;base64,dsjdsaGGUDXGA"}, true},
+		{"gltfBuffer", &Buffer{URI: "data:application/gltf-buffer;base64,dsjdsaGGUDXGA"}, true},
 		{"external", &Buffer{URI: "https://web.com/a"}, false},
 	}
 	for _, tt := range tests {
@@ -73,6 +75,8 @@ func TestImage_IsEmbeddedResource(t *testing.T) {
 	}{
 		{"png", &Image{URI: "data:image/png;base64,dsjdsaGGUDXGA"}, true},
 		{"jpg", &Image{URI: "data:image/png;base64,dsjdsaGGUDXGA"}, true},
+		{"webp", &Image{URI: "data:image/webp;base64,dsjdsaGGUDXGA"}, true},
+		{"ktx2", &Image{URI: "data:image/ktx2;base64,dsjdsaGGUDXGA"}, true},
 		{"external", &Image{URI: "https://web.com/a"}, false},
 	}
 	for _, tt := range tests {
@@ -84,6 +88,88 @@ func TestImage_IsEmbeddedResource(t *testing.T) {
 	}
 }
 
+func TestImage_EmbeddedResource(t *testing.T) {
+	tests := []struct {
+		name string
+		im   *Image
+		want string
+	}{
+		{"mimeType", &Image{MimeType: "image/ktx2", Data: []byte("ktx")}, "data:image/ktx2;base64,a3R4"},
+		{"detected", &Image{Data: []byte("RIFF\x00\x00\x00\x00WEBP")}, "data:image/webp;base64,UklGRgAAAABXRUJQ"},
+		{"unknown", &Image{Data: []byte("ktx")}, "data:application/octet-stream;base64,a3R4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.im.EmbeddedResource()
+			if got := tt.im.URI; got != tt.want {
+				t.Errorf("Image.EmbeddedResource() = %v, want %v", got, tt.want)
+			}
+			if got, want := tt.im.EmbeddedMimeType(), tt.want[5:strings.Index(tt.want, ";")]; got != want {
+				t.Errorf("Image.EmbeddedMimeType() = %v, want %v", got, want)
+			}
+			if got, err := tt.im.MarshalData(); err != nil || !reflect.DeepEqual(got, tt.im.Data) {
+				t.Errorf("Image.MarshalData() = %v, %v, want %v", got, err, tt.im.Data)
+			}
+		})
+	}
+}
+
+func TestDetectImageMimeType(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00"), "image/png"},
+		{"jpeg", []byte("\xff\xd8\xff\xe0"), "image/jpeg"},
+		{"webp", []byte("RIFF\x10\x00\x00\x00WEBPVP8 "), "image/webp"},
+		{"riff", []byte("RIFF\x10\x00\x00\x00WAVE"), ""},
+		{"ktx2", []byte("\xabKTX 20\xbb\r\n\x1a\n"), "image/ktx2"},
+		{"avif", []byte("\x00\x00\x00\x1cftypavif"), "image/avif"},
+		{"gif", []byte("GIF89a"), "image/gif"},
+		{"short", []byte("RIFF"), ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectImageMimeType(tt.data); got != tt.want {
+				t.Errorf("DetectImageMimeType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseDataURI(t *testing.T) {
+	tests := []struct {
+		name      string
+		uri       string
+		mediaType string
+		want      []byte
+		wantErr   bool
+	}{
+		{"base64", "data:image/webp;base64,YWJj", "image/webp", []byte("abc"), false},
+		{"params", "data:Image/PNG;name=a.png;base64,YWJj", "image/png", []byte("abc"), false},
+		{"escapedBase64", "data:image/png;base64,YW%4Aj", "image/png", []byte("abc"), false},
+		{"percent", "data:text/plain,a%20b", "text/plain", []byte("a b"), false},
+		{"noMediaType", "data:,abc", "text/plain", []byte("abc"), false},
+		{"empty", "data:image/png;base64,", "image/png", []byte{}, false},
+		{"noComma", "data:image/png;base64", "", nil, true},
+		{"badBase64", "data:image/png;base64,_", "", nil, true},
+		{"notData", "https://web.com/a", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mediaType, got, err := parseDataURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDataURI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if mediaType != tt.mediaType || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDataURI() = %v, %v, want %v, %v", mediaType, got, tt.mediaType, tt.want)
+			}
+		})
+	}
+}
+
 func TestBuffer_marshalData(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1,6 +1,9 @@
 package gltf
 
-import "math"
+import (
+	"errors"
+	"math"
+)
 
 // NormalizeByte normalize a float32 into a int8
 func NormalizeByte(v float32) int8 {
@@ -139,3 +142,146 @@ func deliniarize(v float32) float32 {
 	}
 	return float32(1.055*math.Pow(float64(v), 1.0/2.4) - 0.055)
 }
+
+// mulMatrix returns the product a*b of two column-major 4x4 matrices.
+func mulMatrix(a, b [16]float32) [16]float32 {
+	var m [16]float32
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			var v float64
+			for k := 0; k < 4; k++ {
+				v += float64(a[k*4+r]) * float64(b[c*4+k])
+			}
+			m[c*4+r] = float32(v)
+		}
+	}
+	return m
+}
+
+// ErrNotDecomposable is returned when a matrix can't be represented
+// as a translation, a rotation and a scale, such as a matrix with shear
+// or projective terms, or with a null scale.
+var ErrNotDecomposable = errors.New("gltf: matrix is not decomposable into translation, rotation and scale")
+
+// ComposeMatrix returns the column-major matrix T*R*S corresponding to
+// the translation t, the unit quaternion r and the scale s.
+func ComposeMatrix(t [3]float32, r [4]float32, s [3]float32) [16]float32 {
+	x, y, z, w := float64(r[0]), float64(r[1]), float64(r[2]), float64(r[3])
+	sx, sy, sz := float64(s[0]), float64(s[1]), float64(s[2])
+	return [16]float32{
+		float32((1 - 2*(y*y+z*z)) * sx), float32(2 * (x*y + z*w) * sx), float32(2 * (x*z - y*w) * sx), 0,
+		float32(2 * (x*y - z*w) * sy), float32((1 - 2*(x*x+z*z)) * sy), float32(2 * (y*z + x*w) * sy), 0,
+		float32(2 * (x*z + y*w) * sz), float32(2 * (y*z - x*w) * sz), float32((1 - 2*(x*x+y*y)) * sz), 0,
+		t[0], t[1], t[2], 1,
+	}
+}
+
+// DecomposeMatrix splits the column-major matrix m into a translation,
+// a unit quaternion rotation and a scale such that m = T*R*S.
+// A negative determinant is represented by a negative x scale.
+// It returns ErrNotDecomposable if m is not an affine transform without shear.
+func DecomposeMatrix(m [16]float32) (t [3]float32, r [4]float32, s [3]float32, err error) {
+	const eps = 1e-4
+	if math.Abs(float64(m[3])) > eps || math.Abs(float64(m[7])) > eps || math.Abs(float64(m[11])) > eps || math.Abs(float64(m[15])-1) > eps {
+		return DefaultTranslation, DefaultRotation, DefaultScale, ErrNotDecomposable
+	}
+	var cols [3][3]float64
+	var scale [3]float64
+	for c := 0; c < 3; c++ {
+		cols[c] = [3]float64{float64(m[c*4]), float64(m[c*4+1]), float64(m[c*4+2])}
+		scale[c] = math.Sqrt(dot3(cols[c], cols[c]))
+		if scale[c] < 1e-12 {
+			return DefaultTranslation, DefaultRotation, DefaultScale, ErrNotDecomposable
+		}
+	}
+	det := cols[0][0]*(cols[1][1]*cols[2][2]-cols[2][1]*cols[1][2]) -
+		cols[1][0]*(cols[0][1]*cols[2][2]-cols[2][1]*cols[0][2]) +
+		cols[2][0]*(cols[0][1]*cols[1][2]-cols[1][1]*cols[0][2])
+	if det < 0 {
+		scale[0] = -scale[0]
+	}
+	for c := 0; c < 3; c++ {
+		for i := range cols[c] {
+			cols[c][i] /= scale[c]
+		}
+	}
+	if math.Abs(dot3(cols[0], cols[1])) > eps || math.Abs(dot3(cols[0], cols[2])) > eps || math.Abs(dot3(cols[1], cols[2])) > eps {
+		return DefaultTranslation, DefaultRotation, DefaultScale, ErrNotDecomposable
+	}
+
+	// Rotation matrix to quaternion, cols[c][r] is the element at row r and column c.
+	var q [4]float64
+	if trace := cols[0][0] + cols[1][1] + cols[2][2]; trace > 0 {
+		k := 0.5 / math.Sqrt(trace+1)
+		q = [4]float64{(cols[1][2] - cols[2][1]) * k, (cols[2][0] - cols[0][2]) * k, (cols[0][1] - cols[1][0]) * k, 0.25 / k}
+	} else if cols[0][0] > cols[1][1] && cols[0][0] > cols[2][2] {
+		k := 2 * math.Sqrt(1+cols[0][0]-cols[1][1]-cols[2][2])
+		q = [4]float64{0.25 * k, (cols[1][0] + cols[0][1]) / k, (cols[2][0] + cols[0][2]) / k, (cols[1][2] - cols[2][1]) / k}
+	} else if cols[1][1] > cols[2][2] {
+		k := 2 * math.Sqrt(1+cols[1][1]-cols[0][0]-cols[2][2])
+		q = [4]float64{(cols[1][0] + cols[0][1]) / k, 0.25 * k, (cols[2][1] + cols[1][2]) / k, (cols[2][0] - cols[0][2]) / k}
+	} else {
+		k := 2 * math.Sqrt(1+cols[2][2]-cols[0][0]-cols[1][1])
+		q = [4]float64{(cols[2][0] + cols[0][2]) / k, (cols[2][1] + cols[1][2]) / k, 0.25 * k, (cols[0][1] - cols[1][0]) / k}
+	}
+	if q[3] < 0 {
+		q = [4]float64{-q[0], -q[1], -q[2], -q[3]}
+	}
+	l := math.Sqrt(q[0]*q[0] + q[1]*q[1] + q[2]*q[2] + q[3]*q[3])
+	t = [3]float32{m[12], m[13], m[14]}
+	r = [4]float32{float32(q[0] / l), float32(q[1] / l), float32(q[2] / l), float32(q[3] / l)}
+	s = [3]float32{float32(scale[0]), float32(scale[1]), float32(scale[2])}
+	return t, r, s, nil
+}
+
+// ErrSingularMatrix is returned when a matrix has no inverse.
+var ErrSingularMatrix = errors.New("gltf: matrix is not invertible")
+
+// InvertMatrix returns the inverse of the column-major matrix m.
+// It returns ErrSingularMatrix if m has no inverse.
+func InvertMatrix(m [16]float32) ([16]float32, error) {
+	// Gauss-Jordan elimination with partial pivoting, a[r] is the row r of [m | I].
+	var a [4][8]float64
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			a[r][c] = float64(m[c*4+r])
+		}
+		a[r][4+r] = 1
+	}
+	for c := 0; c < 4; c++ {
+		pivot := c
+		for r := c + 1; r < 4; r++ {
+			if math.Abs(a[r][c]) > math.Abs(a[pivot][c]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][c]) < 1e-12 {
+			return DefaultMatrix, ErrSingularMatrix
+		}
+		a[c], a[pivot] = a[pivot], a[c]
+		for k := c + 1; k < 8; k++ {
+			a[c][k] /= a[c][c]
+		}
+		a[c][c] = 1
+		for r := 0; r < 4; r++ {
+			if r == c || a[r][c] == 0 {
+				continue
+			}
+			f := a[r][c]
+			for k := c; k < 8; k++ {
+				a[r][k] -= f * a[c][k]
+			}
+		}
+	}
+	var inv [16]float32
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			inv[c*4+r] = float32(a[r][4+c])
+		}
+	}
+	return inv, nil
+}
+
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
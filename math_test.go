@@ -236,3 +236,107 @@ func TestDenormalizeRGB64(t *testing.T) {
 		})
 	}
 }
+
+func TestDecomposeMatrix(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       [3]float32
+		r       [4]float32
+		s       [3]float32
+		wantErr bool
+	}{
+		{"identity", DefaultTranslation, DefaultRotation, DefaultScale, false},
+		{"trs", [3]float32{1, 2, 3}, [4]float32{0.18257419, 0.36514837, 0.5477226, 0.73029673}, [3]float32{2, 3, 4}, false},
+		{"rotationX180", [3]float32{-1, 0, 0}, [4]float32{1, 0, 0, 0}, [3]float32{1, 1, 1}, false},
+		{"rotationY180", DefaultTranslation, [4]float32{0, 1, 0, 0}, [3]float32{0.5, 0.5, 0.5}, false},
+		{"rotationZ180", DefaultTranslation, [4]float32{0, 0, 1, 0}, [3]float32{1, 1, 1}, false},
+		{"mirror", DefaultTranslation, DefaultRotation, [3]float32{-1, 1, 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ComposeMatrix(tt.t, tt.r, tt.s)
+			gotT, gotR, gotS, err := DecomposeMatrix(m)
+			if err != nil {
+				t.Fatalf("DecomposeMatrix() error = %v", err)
+			}
+			if !matrixAlmostEqual(ComposeMatrix(gotT, gotR, gotS), m) {
+				t.Errorf("DecomposeMatrix() = %v %v %v, want %v %v %v", gotT, gotR, gotS, tt.t, tt.r, tt.s)
+			}
+			for i := range tt.s {
+				if math.Abs(float64(gotS[i]-tt.s[i])) > 1e-5 {
+					t.Errorf("DecomposeMatrix() scale = %v, want %v", gotS, tt.s)
+				}
+			}
+		})
+	}
+}
+
+func TestDecomposeMatrix_error(t *testing.T) {
+	tests := []struct {
+		name string
+		m    [16]float32
+	}{
+		{"shear", [16]float32{1, 0, 0, 0, 1, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}},
+		{"projective", [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, -1, 0, 0, 0, 0}},
+		{"nullScale", [16]float32{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := DecomposeMatrix(tt.m); err != ErrNotDecomposable {
+				t.Errorf("DecomposeMatrix() error = %v, want %v", err, ErrNotDecomposable)
+			}
+		})
+	}
+}
+
+func TestInvertMatrix(t *testing.T) {
+	tests := []struct {
+		name string
+		m    [16]float32
+	}{
+		{"identity", DefaultMatrix},
+		{"trs", ComposeMatrix([3]float32{1, -2, 3}, [4]float32{0, 0.7071068, 0, 0.7071068}, [3]float32{2, 3, 4})},
+		{"shear", [16]float32{1, 0, 0, 0, 1, 1, 0, 0, 0, 0, 1, 0, 5, 0, 0, 1}},
+		{"projective", [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, -1, 0, 0, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, err := InvertMatrix(tt.m)
+			if err != nil {
+				t.Fatalf("InvertMatrix() error = %v", err)
+			}
+			got := mulMatrix(tt.m, inv)
+			for i := range got {
+				if math.Abs(float64(got[i]-DefaultMatrix[i])) > 1e-5 {
+					t.Fatalf("m*InvertMatrix(m) = %v, want identity", got)
+				}
+			}
+		})
+	}
+	if _, err := InvertMatrix([16]float32{}); err != ErrSingularMatrix {
+		t.Errorf("InvertMatrix() error = %v, want %v", err, ErrSingularMatrix)
+	}
+}
+
+func TestNode_UseTRS(t *testing.T) {
+	n := &Node{Matrix: [16]float32{0, 2, 0, 0, -2, 0, 0, 0, 0, 0, 2, 0, 1, 2, 3, 1}}
+	m := n.LocalMatrix()
+	if err := n.UseTRS(); err != nil {
+		t.Fatalf("Node.UseTRS() error = %v", err)
+	}
+	if n.Matrix != DefaultMatrix || !matrixAlmostEqual(n.LocalMatrix(), m) {
+		t.Errorf("Node.UseTRS() = %v %v %v %v", n.Matrix, n.Translation, n.Rotation, n.Scale)
+	}
+	if diff := deep.Equal(n.Translation, [3]float32{1, 2, 3}); diff != nil {
+		t.Errorf("Node.UseTRS() translation = %v", diff)
+	}
+	n.UseMatrix()
+	if !matrixAlmostEqual(n.Matrix, m) || n.Rotation != DefaultRotation || n.Scale != DefaultScale || n.Translation != DefaultTranslation {
+		t.Errorf("Node.UseMatrix() = %v %v %v %v", n.Matrix, n.Translation, n.Rotation, n.Scale)
+	}
+
+	sheared := &Node{Matrix: [16]float32{1, 0, 0, 0, 1, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}}
+	if err := sheared.UseTRS(); err != ErrNotDecomposable || sheared.Matrix[4] != 1 {
+		t.Errorf("Node.UseTRS() error = %v, node = %v", err, sheared.Matrix)
+	}
+}
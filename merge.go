@@ -0,0 +1,146 @@
+package gltf
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// MergeOptions configures MergeWithOptions.
+type MergeOptions struct {
+	// CombineScenes adds the root nodes of the scenes of the sources to the
+	// default scene of dst instead of appending the scenes of the sources to dst.
+	CombineScenes bool
+}
+
+// Merge appends the content of srcs to dst, keeping the scenes separate.
+// See MergeWithOptions.
+func Merge(dst *Document, srcs ...*Document) error {
+	return MergeWithOptions(dst, MergeOptions{}, srcs...)
+}
+
+// MergeWithOptions appends the objects of each document of srcs to dst,
+// rewriting their indices, including the ones held by the extension payloads
+// that implement IndexRemapper. The document extensions of a source are added to dst,
+// or combined with the ones of dst if they implement ExtensionMerger.
+// ExtensionsUsed and ExtensionsRequired are the union of the ones of all documents.
+//
+// The objects of srcs are moved to dst, so srcs must not be used afterwards.
+// Buffers are not concatenated, use Pack to obtain a single buffer.
+// If a source can't be merged, for example because one of its extension payloads
+// was not decoded, an error is returned and dst contains the previous sources.
+func MergeWithOptions(dst *Document, opts MergeOptions, srcs ...*Document) error {
+	for i, src := range srcs {
+		if err := mergeDocument(dst, src, opts); err != nil {
+			return fmt.Errorf("gltf: merging document %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func mergeDocument(dst, src *Document, opts MergeOptions) error {
+	if err := checkRemappable(src); err != nil {
+		return err
+	}
+	offsets := map[string]uint32{
+		PropertyAccessors:   uint32(len(dst.Accessors)),
+		PropertyAnimations:  uint32(len(dst.Animations)),
+		PropertyBuffers:     uint32(len(dst.Buffers)),
+		PropertyBufferViews: uint32(len(dst.BufferViews)),
+		PropertyCameras:     uint32(len(dst.Cameras)),
+		PropertyImages:      uint32(len(dst.Images)),
+		PropertyMaterials:   uint32(len(dst.Materials)),
+		PropertyMeshes:      uint32(len(dst.Meshes)),
+		PropertyNodes:       uint32(len(dst.Nodes)),
+		PropertySamplers:    uint32(len(dst.Samplers)),
+		PropertyScenes:      uint32(len(dst.Scenes)),
+		PropertySkins:       uint32(len(dst.Skins)),
+		PropertyTextures:    uint32(len(dst.Textures)),
+	}
+	remap := func(property string, index uint32) uint32 {
+		return index + offsets[property]
+	}
+
+	// Document extensions are merged first, as they define the offsets
+	// of the arrays they contain, and are the only step that can fail.
+	exts := make(Extensions, len(dst.Extensions)+len(src.Extensions))
+	for name, v := range dst.Extensions {
+		exts[name] = v
+	}
+	for name, v := range src.Extensions {
+		v = remapPayload(v, remap)
+		cur, ok := exts[name]
+		if !ok {
+			exts[name] = v
+			continue
+		}
+		if m, ok := cur.(ExtensionMerger); ok {
+			merged, extOffsets, err := m.MergeExtension(payloadValue(v))
+			if err != nil {
+				return err
+			}
+			for property, offset := range extOffsets {
+				offsets[property] = offset
+			}
+			exts[name] = payloadLike(cur, merged)
+		} else if !reflect.DeepEqual(payloadValue(cur), payloadValue(v)) {
+			return fmt.Errorf("gltf: extension %s can't be merged", name)
+		}
+	}
+	if len(exts) > 0 {
+		dst.Extensions = exts
+	}
+
+	remapDocument(src, remap)
+	if opts.CombineScenes {
+		combineScenes(dst, src)
+	} else {
+		if dst.Scene == nil && len(dst.Scenes) == 0 {
+			dst.Scene = src.Scene
+		}
+		dst.Scenes = append(dst.Scenes, src.Scenes...)
+	}
+	dst.Accessors = append(dst.Accessors, src.Accessors...)
+	dst.Animations = append(dst.Animations, src.Animations...)
+	dst.Buffers = append(dst.Buffers, src.Buffers...)
+	dst.BufferViews = append(dst.BufferViews, src.BufferViews...)
+	dst.Cameras = append(dst.Cameras, src.Cameras...)
+	dst.Images = append(dst.Images, src.Images...)
+	dst.Materials = append(dst.Materials, src.Materials...)
+	dst.Meshes = append(dst.Meshes, src.Meshes...)
+	dst.Nodes = append(dst.Nodes, src.Nodes...)
+	dst.Samplers = append(dst.Samplers, src.Samplers...)
+	dst.Skins = append(dst.Skins, src.Skins...)
+	dst.Textures = append(dst.Textures, src.Textures...)
+	for _, name := range src.ExtensionsUsed {
+		dst.AddExtensionUsed(name)
+	}
+	for _, name := range src.ExtensionsRequired {
+		if !slices.Contains(dst.ExtensionsRequired, name) {
+			dst.ExtensionsRequired = append(dst.ExtensionsRequired, name)
+		}
+	}
+	return nil
+}
+
+// combineScenes adds the root nodes of every scene of src,
+// already remapped, to the default scene of dst.
+func combineScenes(dst, src *Document) {
+	if len(src.Scenes) == 0 {
+		return
+	}
+	if dst.Scene == nil || int(*dst.Scene) >= len(dst.Scenes) {
+		if len(dst.Scenes) == 0 {
+			dst.Scenes = append(dst.Scenes, new(Scene))
+		}
+		dst.Scene = Index(0)
+	}
+	scene := dst.Scenes[*dst.Scene]
+	for _, s := range src.Scenes {
+		for _, n := range s.Nodes {
+			if !slices.Contains(scene.Nodes, n) {
+				scene.Nodes = append(scene.Nodes, n)
+			}
+		}
+	}
+}
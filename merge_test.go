@@ -0,0 +1,174 @@
+package gltf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type testLightIndex uint32
+
+func (l testLightIndex) RemapIndices(remap IndexRemapFunc) interface{} {
+	return testLightIndex(remap("lights", uint32(l)))
+}
+
+type testLights []string
+
+func (l testLights) MergeExtension(other interface{}) (interface{}, map[string]uint32, error) {
+	o, ok := other.(testLights)
+	if !ok {
+		return nil, nil, errors.New("bad type")
+	}
+	return append(append(testLights{}, l...), o...), map[string]uint32{"lights": uint32(len(l))}, nil
+}
+
+type testAccessorRef struct{ Accessor uint32 }
+
+func (a testAccessorRef) RemapIndices(remap IndexRemapFunc) interface{} {
+	return testAccessorRef{remap(PropertyAccessors, a.Accessor)}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    MergeOptions
+		copies  int // Number of times src is merged.
+		modify  func(dst, src *Document)
+		wantErr bool
+		check   func(t *testing.T, dst, a, b *Document)
+	}{
+		{"merge", MergeOptions{}, 1, nil, false, func(t *testing.T, dst, want, b *Document) {
+			want.ExtensionsUsed = []string{"lights", "other", "ref"}
+			want.ExtensionsRequired = []string{"ref"}
+			want.Extensions = Extensions{"lights": testLights{"a", "b", "c"}}
+			want.Scenes = append(want.Scenes, &Scene{Nodes: []uint32{1}})
+			b.Nodes[0].Children = []uint32{2}
+			b.Nodes[0].Skin = Index(0)
+			b.Nodes[0].Extensions = Extensions{"lights": testLightIndex(2)}
+			b.Nodes[1].Mesh = Index(1)
+			b.Nodes[1].Extensions = Extensions{"ref": &testAccessorRef{2}}
+			want.Nodes = append(want.Nodes, b.Nodes...)
+			b.Skins[0] = &Skin{Joints: []uint32{1, 2}, Skeleton: Index(1), InverseBindMatrices: Index(1)}
+			want.Skins = b.Skins
+			want.Cameras = b.Cameras
+			b.Meshes[0].Primitives[0] = &Primitive{
+				Attributes: Attribute{POSITION: 1, NORMAL: 2},
+				Indices:    Index(2),
+				Material:   Index(1),
+				Targets:    []Attribute{{POSITION: 2}},
+			}
+			want.Meshes = append(want.Meshes, b.Meshes...)
+			want.Materials = append(want.Materials, b.Materials...)
+			want.Textures, want.Images, want.Samplers = b.Textures, b.Images, b.Samplers
+			want.Images[0].BufferView = Index(2)
+			want.Accessors = append(want.Accessors,
+				&Accessor{BufferView: Index(1)},
+				&Accessor{BufferView: Index(1), Sparse: &Sparse{Indices: SparseIndices{BufferView: 2}, Values: SparseValues{BufferView: 1}}},
+			)
+			b.Animations[0].Channels[0].Target.Node = Index(2)
+			b.Animations[0].Samplers[0] = &AnimationSampler{Input: 1, Output: 2}
+			want.Animations = b.Animations
+			want.BufferViews = append(want.BufferViews, &BufferView{Buffer: 1}, &BufferView{Buffer: 1})
+			want.Buffers = append(want.Buffers, b.Buffers...)
+			if diff := deep.Equal(dst, want); diff != nil {
+				t.Errorf("Merge() = %v", diff)
+			}
+		}},
+		{"combineScenes", MergeOptions{CombineScenes: true}, 2, func(dst, src *Document) {
+			dst.Scene = nil
+			dst.Scenes = nil
+		}, false, func(t *testing.T, dst, a, b *Document) {
+			if diff := deep.Equal(dst.Scenes, []*Scene{{Nodes: []uint32{1, 3}}}); diff != nil {
+				t.Errorf("MergeWithOptions() scenes = %v", diff)
+			}
+			if dst.Scene == nil || *dst.Scene != 0 {
+				t.Errorf("MergeWithOptions() scene = %v", dst.Scene)
+			}
+			if got := dst.Extensions["lights"]; deep.Equal(got, testLights{"a", "b", "c", "b", "c"}) != nil {
+				t.Errorf("MergeWithOptions() lights = %v", got)
+			}
+			if got := dst.Nodes[3].Extensions["lights"]; got != testLightIndex(4) {
+				t.Errorf("MergeWithOptions() light index = %v", got)
+			}
+		}},
+		{"extensionConflict", MergeOptions{}, 1, func(dst, src *Document) {
+			dst.Extensions["ref"] = testAccessorRef{0}
+			src.Extensions["ref"] = testAccessorRef{0}
+		}, true, func(t *testing.T, dst, a, b *Document) {
+			if len(dst.Nodes) != 1 || len(dst.Extensions) != 2 {
+				t.Errorf("Merge() modified dst on error")
+			}
+		}},
+		{"sameExtension", MergeOptions{}, 1, func(dst, src *Document) {
+			dst.Extensions["same"] = "value"
+			src.Extensions["same"] = "value"
+		}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := &Document{
+				ExtensionsUsed: []string{"lights", "other"},
+				Extensions:     Extensions{"lights": testLights{"a"}},
+				Scene:          Index(0),
+				Scenes:         []*Scene{{Nodes: []uint32{0}}},
+				Nodes:          []*Node{{Mesh: Index(0), Extensions: Extensions{"lights": testLightIndex(0)}}},
+				Meshes:         []*Mesh{{Primitives: []*Primitive{{Attributes: Attribute{POSITION: 0}, Material: Index(0)}}}},
+				Materials:      []*Material{{Name: "a"}},
+				Accessors:      []*Accessor{{BufferView: Index(0)}},
+				BufferViews:    []*BufferView{{Buffer: 0}},
+				Buffers:        []*Buffer{{ByteLength: 1}},
+			}
+			src := &Document{
+				ExtensionsUsed:     []string{"lights", "ref"},
+				ExtensionsRequired: []string{"ref"},
+				Extensions:         Extensions{"lights": testLights{"b", "c"}},
+				Scene:              Index(0),
+				Scenes:             []*Scene{{Nodes: []uint32{0}}},
+				Nodes: []*Node{
+					{Children: []uint32{1}, Skin: Index(0), Extensions: Extensions{"lights": testLightIndex(1)}},
+					{Mesh: Index(0), Camera: Index(0), Extensions: Extensions{"ref": &testAccessorRef{1}}},
+				},
+				Cameras: []*Camera{{Perspective: &Perspective{}}},
+				Skins:   []*Skin{{Joints: []uint32{0, 1}, Skeleton: Index(0), InverseBindMatrices: Index(0)}},
+				Meshes: []*Mesh{{Primitives: []*Primitive{{
+					Attributes: Attribute{POSITION: 0, NORMAL: 1},
+					Indices:    Index(1),
+					Material:   Index(0),
+					Targets:    []Attribute{{POSITION: 1}},
+				}}}},
+				Materials: []*Material{{
+					PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}},
+					NormalTexture:        &NormalTexture{Index: Index(0)},
+				}},
+				Textures: []*Texture{{Source: Index(0), Sampler: Index(0)}},
+				Images:   []*Image{{BufferView: Index(1), MimeType: "image/png"}},
+				Samplers: []*Sampler{{}},
+				Accessors: []*Accessor{
+					{BufferView: Index(0)},
+					{BufferView: Index(0), Sparse: &Sparse{Indices: SparseIndices{BufferView: 1}, Values: SparseValues{BufferView: 0}}},
+				},
+				Animations: []*Animation{{
+					Channels: []*Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(1)}}},
+					Samplers: []*AnimationSampler{{Input: 0, Output: 1}},
+				}},
+				BufferViews: []*BufferView{{Buffer: 0}, {Buffer: 0}},
+				Buffers:     []*Buffer{{ByteLength: 2}},
+			}
+			if tt.modify != nil {
+				tt.modify(dst, src)
+			}
+			a, b := dst.Clone(), src.Clone()
+			srcs := []*Document{src}
+			for i := 1; i < tt.copies; i++ {
+				srcs = append(srcs, b.Clone())
+			}
+			if err := MergeWithOptions(dst, tt.opts, srcs...); (err != nil) != tt.wantErr {
+				t.Fatalf("MergeWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, dst, a, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package modeler
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/flywave/gltf"
+)
+
+// AnimationBuilder writes the keyframes of an animation into a document.
+// Tracks with the same keyframe times share the same input accessor.
+//
+// The values of CUBICSPLINE tracks contain three elements per keyframe,
+// in the order defined by the specification: in-tangent, value and out-tangent.
+type AnimationBuilder struct {
+	doc       *gltf.Document
+	animation *gltf.Animation
+	inputs    map[string]uint32
+}
+
+// NewAnimationBuilder returns a builder of an animation named name whose data is written to doc.
+func NewAnimationBuilder(doc *gltf.Document, name string) *AnimationBuilder {
+	return &AnimationBuilder{
+		doc:       doc,
+		animation: &gltf.Animation{Name: name},
+		inputs:    make(map[string]uint32),
+	}
+}
+
+// Translation adds a track that animates the translation of node.
+func (b *AnimationBuilder) Translation(node uint32, times []float32, values [][3]float32, interpolation gltf.Interpolation) error {
+	if err := b.checkTrack(node, gltf.TRSTranslation, times, len(values), interpolation); err != nil {
+		return err
+	}
+	b.addChannel(node, gltf.TRSTranslation, times, WriteAccessor(b.doc, gltf.TargetNone, values), interpolation)
+	return nil
+}
+
+// Rotation adds a track that animates the rotation of node.
+// values are unit quaternions in (x, y, z, w) order.
+func (b *AnimationBuilder) Rotation(node uint32, times []float32, values [][4]float32, interpolation gltf.Interpolation) error {
+	if err := b.checkTrack(node, gltf.TRSRotation, times, len(values), interpolation); err != nil {
+		return err
+	}
+	b.addChannel(node, gltf.TRSRotation, times, WriteAccessor(b.doc, gltf.TargetNone, values), interpolation)
+	return nil
+}
+
+// Scale adds a track that animates the scale of node.
+func (b *AnimationBuilder) Scale(node uint32, times []float32, values [][3]float32, interpolation gltf.Interpolation) error {
+	if err := b.checkTrack(node, gltf.TRSScale, times, len(values), interpolation); err != nil {
+		return err
+	}
+	b.addChannel(node, gltf.TRSScale, times, WriteAccessor(b.doc, gltf.TargetNone, values), interpolation)
+	return nil
+}
+
+// Weights adds a track that animates the morph target weights of the mesh instantiated by node.
+// Each element of values holds the weight of every morph target.
+func (b *AnimationBuilder) Weights(node uint32, times []float32, values [][]float32, interpolation gltf.Interpolation) error {
+	if err := b.checkTrack(node, gltf.TRSWeights, times, len(values), interpolation); err != nil {
+		return err
+	}
+	targets := len(values[0])
+	if n := b.doc.Nodes[node]; n.Mesh != nil && int(*n.Mesh) < len(b.doc.Meshes) {
+		if prims := b.doc.Meshes[*n.Mesh].Primitives; len(prims) > 0 {
+			targets = len(prims[0].Targets)
+		}
+	}
+	if targets == 0 {
+		return fmt.Errorf("gltf: node %d has no morph targets", node)
+	}
+	data := make([]float32, 0, len(values)*targets)
+	for _, v := range values {
+		if len(v) != targets {
+			return fmt.Errorf("gltf: weights keyframe has %d values, want %d", len(v), targets)
+		}
+		data = append(data, v...)
+	}
+	b.addChannel(node, gltf.TRSWeights, times, WriteAccessor(b.doc, gltf.TargetNone, data), interpolation)
+	return nil
+}
+
+// Build adds the animation to the document.
+// If success it returns the index of the new animation.
+func (b *AnimationBuilder) Build() (uint32, error) {
+	if len(b.animation.Channels) == 0 {
+		return 0, errors.New("gltf: animation has no tracks")
+	}
+	b.doc.Animations = append(b.doc.Animations, b.animation)
+	return uint32(len(b.doc.Animations) - 1), nil
+}
+
+func (b *AnimationBuilder) checkTrack(node uint32, path gltf.TRSProperty, times []float32, count int, interpolation gltf.Interpolation) error {
+	if int(node) >= len(b.doc.Nodes) {
+		return fmt.Errorf("gltf: node %d does not exist", node)
+	}
+	for _, c := range b.animation.Channels {
+		if *c.Target.Node == node && c.Target.Path == path {
+			return fmt.Errorf("gltf: node %d %v is already animated", node, path)
+		}
+	}
+	if len(times) == 0 {
+		return errors.New("gltf: animation track has no keyframes")
+	}
+	for i, t := range times {
+		if t < 0 || math.IsNaN(float64(t)) || (i > 0 && t <= times[i-1]) {
+			return errors.New("gltf: keyframe times must be non-negative and strictly increasing")
+		}
+	}
+	want := len(times)
+	switch interpolation {
+	case gltf.InterpolationLinear, gltf.InterpolationStep:
+	case gltf.InterpolationCubicSpline:
+		want *= 3
+	default:
+		return fmt.Errorf("gltf: interpolation %v not supported", interpolation)
+	}
+	if count != want {
+		return fmt.Errorf("gltf: %v track has %d values, want %d", interpolation, count, want)
+	}
+	return nil
+}
+
+func (b *AnimationBuilder) addChannel(node uint32, path gltf.TRSProperty, times []float32, output uint32, interpolation gltf.Interpolation) {
+	key := make([]byte, 4*len(times))
+	for i, t := range times {
+		binary.LittleEndian.PutUint32(key[4*i:], math.Float32bits(t))
+	}
+	input, ok := b.inputs[string(key)]
+	if !ok {
+		input = WriteAccessor(b.doc, gltf.TargetNone, times)
+		b.doc.Accessors[input].Min = []float32{times[0]}
+		b.doc.Accessors[input].Max = []float32{times[len(times)-1]}
+		b.inputs[string(key)] = input
+	}
+	sampler := uint32(len(b.animation.Samplers))
+	b.animation.Samplers = append(b.animation.Samplers, &gltf.AnimationSampler{
+		Input:         input,
+		Interpolation: interpolation,
+		Output:        output,
+	})
+	b.animation.Channels = append(b.animation.Channels, &gltf.Channel{
+		Sampler: gltf.Index(sampler),
+		Target:  gltf.ChannelTarget{Node: gltf.Index(node), Path: path},
+	})
+}
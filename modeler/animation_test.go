@@ -0,0 +1,93 @@
+package modeler
+
+import (
+	"testing"
+
+	"github.com/flywave/gltf"
+	"github.com/go-test/deep"
+)
+
+func TestAnimationBuilder(t *testing.T) {
+	doc := gltf.NewDocument()
+	doc.Meshes = []*gltf.Mesh{{Primitives: []*gltf.Primitive{{Targets: []gltf.Attribute{{}, {}}}}}}
+	doc.Nodes = []*gltf.Node{{}, {Mesh: gltf.Index(0)}}
+	times := []float32{0, 1, 2}
+	b := NewAnimationBuilder(doc, "anim")
+	if err := b.Translation(0, times, [][3]float32{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}}, gltf.InterpolationLinear); err != nil {
+		t.Fatalf("Translation() error = %v", err)
+	}
+	if err := b.Rotation(0, []float32{0, 1, 2}, [][4]float32{{0, 0, 0, 1}, {0, 0, 0, 1}, {0, 0, 0, 1}}, gltf.InterpolationStep); err != nil {
+		t.Fatalf("Rotation() error = %v", err)
+	}
+	if err := b.Scale(0, []float32{0, 4}, [][3]float32{{1, 1, 1}, {0, 0, 0}, {1, 1, 1}, {1, 1, 1}, {2, 2, 2}, {1, 1, 1}}, gltf.InterpolationCubicSpline); err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+	if err := b.Weights(1, times, [][]float32{{0, 1}, {1, 0}, {0, 0}}, gltf.InterpolationLinear); err != nil {
+		t.Fatalf("Weights() error = %v", err)
+	}
+	index, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	anim := doc.Animations[index]
+	if anim.Name != "anim" || len(anim.Channels) != 4 || len(anim.Samplers) != 4 {
+		t.Fatalf("Build() = %+v", anim)
+	}
+	s := anim.Samplers
+	if s[0].Input != s[1].Input || s[0].Input != s[3].Input || s[0].Input == s[2].Input {
+		t.Error("Build() expected tracks with the same times to share the input accessor")
+	}
+	input := doc.Accessors[s[2].Input]
+	if diff := deep.Equal([][]float32{input.Min, input.Max}, [][]float32{{0}, {4}}); diff != nil {
+		t.Errorf("Build() input bounds = %v", diff)
+	}
+	if doc.Accessors[s[2].Output].Count != 6 || s[2].Interpolation != gltf.InterpolationCubicSpline {
+		t.Errorf("Build() cubic spline sampler = %+v", s[2])
+	}
+	weights, err := ReadAccessor(doc, doc.Accessors[s[3].Output], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(weights, []float32{0, 1, 1, 0, 0, 0}); diff != nil {
+		t.Errorf("Build() weights = %v", diff)
+	}
+	if c := anim.Channels[3]; *c.Sampler != 3 || *c.Target.Node != 1 || c.Target.Path != gltf.TRSWeights {
+		t.Errorf("Build() channel = %+v", c)
+	}
+}
+
+func TestAnimationBuilder_Error(t *testing.T) {
+	doc := gltf.NewDocument()
+	doc.Nodes = []*gltf.Node{{}}
+	b := NewAnimationBuilder(doc, "")
+	if _, err := b.Build(); err == nil {
+		t.Error("Build() expected error with no tracks")
+	}
+	value := [][3]float32{{0, 0, 0}}
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"missing node", b.Translation(1, []float32{0}, value, gltf.InterpolationLinear)},
+		{"no keyframes", b.Translation(0, nil, nil, gltf.InterpolationLinear)},
+		{"decreasing", b.Translation(0, []float32{1, 0}, [][3]float32{{}, {}}, gltf.InterpolationLinear)},
+		{"repeated", b.Translation(0, []float32{1, 1}, [][3]float32{{}, {}}, gltf.InterpolationLinear)},
+		{"negative", b.Translation(0, []float32{-1}, value, gltf.InterpolationLinear)},
+		{"count", b.Translation(0, []float32{0, 1}, value, gltf.InterpolationLinear)},
+		{"cubic count", b.Translation(0, []float32{0}, value, gltf.InterpolationCubicSpline)},
+		{"no targets", b.Weights(0, []float32{0}, [][]float32{{}}, gltf.InterpolationLinear)},
+		{"weights count", b.Weights(0, []float32{0, 1}, [][]float32{{1}, {1, 2}}, gltf.InterpolationLinear)},
+	}
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+	if err := b.Scale(0, []float32{0}, value, gltf.InterpolationLinear); err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+	if err := b.Scale(0, []float32{0}, value, gltf.InterpolationLinear); err == nil {
+		t.Error("Scale() expected error for an already animated property")
+	}
+}
@@ -0,0 +1,268 @@
+package modeler
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/flywave/gltf"
+	"github.com/flywave/gltf/binary"
+)
+
+// DedupOptions configures DedupWithOptions.
+// Each field enables the merge of one kind of object.
+type DedupOptions struct {
+	// Accessors merges the accessors with the same component type, type, count,
+	// normalization, bounds, buffer view target, extensions, extras and data,
+	// as read by ReadAccessor. Accessors whose data is not stored in a buffer,
+	// such as the ones compressed by KHR_draco_mesh_compression, are never merged.
+	Accessors bool
+	// Images merges the images with the same mime type, extensions, extras and bytes.
+	// Images referenced by an URI whose Data is not loaded are only merged if they have the same URI.
+	Images bool
+	// Samplers merges the samplers with the same filters, wrap modes, extensions and extras.
+	Samplers bool
+	// Textures merges the textures with the same sampler, source, extensions and extras.
+	Textures bool
+	// Materials merges the materials with the same definition, including their extensions.
+	Materials bool
+}
+
+// Dedup merges all the kinds of duplicated objects supported by DedupWithOptions.
+func Dedup(doc *gltf.Document) error {
+	return DedupWithOptions(doc, DedupOptions{
+		Accessors: true,
+		Images:    true,
+		Samplers:  true,
+		Textures:  true,
+		Materials: true,
+	})
+}
+
+// DedupWithOptions replaces the objects that are exact duplicates of a previous
+// object of the same kind by that object, rewriting the indices that reference them,
+// including the ones held by the extension payloads that implement gltf.IndexRemapper.
+// Names are not compared. Textures are compared as if their images and samplers
+// were already merged, and materials as if their textures were,
+// so that textures using duplicated images are also merged.
+//
+// The buffer views of the removed accessors and images are kept,
+// use gltf.Prune to remove them.
+// If an object can't be read or an extension payload was not decoded,
+// an error is returned and doc is not modified.
+func DedupWithOptions(doc *gltf.Document, opts DedupOptions) error {
+	merged := make(map[string][]uint32)
+	removed := false
+	add := func(property string, keys []*dedupKey) {
+		indices, n := dedupIndices(keys)
+		merged[property] = indices
+		removed = removed || n < len(indices)
+	}
+	remap := func(property string, index uint32) uint32 {
+		if indices, ok := merged[property]; ok && int(index) < len(indices) {
+			return indices[index]
+		}
+		return index
+	}
+	// rewritten returns part, or a copy of it if its references
+	// to the objects already merged have to be rewritten.
+	rewritten := func(part *gltf.Document) (*gltf.Document, error) {
+		if !removed {
+			return part, nil
+		}
+		c := part.Clone()
+		return c, gltf.RemapDocument(c, remap)
+	}
+
+	if opts.Accessors {
+		keys, err := hashObjects(doc.Accessors, func(acr *gltf.Accessor) ([]byte, error) {
+			return accessorData(doc, acr)
+		})
+		if err != nil {
+			return err
+		}
+		add(gltf.PropertyAccessors, keys)
+	}
+	if opts.Images {
+		keys, err := hashObjects(doc.Images, func(im *gltf.Image) ([]byte, error) {
+			return imageData(doc, im)
+		})
+		if err != nil {
+			return err
+		}
+		add(gltf.PropertyImages, keys)
+	}
+	if opts.Samplers {
+		keys, err := hashObjects(doc.Samplers, func(s *gltf.Sampler) ([]byte, error) {
+			c := *s
+			c.Name = ""
+			return json.Marshal(&c)
+		})
+		if err != nil {
+			return err
+		}
+		add(gltf.PropertySamplers, keys)
+	}
+	if opts.Textures {
+		part, err := rewritten(&gltf.Document{Textures: doc.Textures})
+		if err != nil {
+			return err
+		}
+		keys, err := hashObjects(part.Textures, func(t *gltf.Texture) ([]byte, error) {
+			c := *t
+			c.Name = ""
+			return json.Marshal(&c)
+		})
+		if err != nil {
+			return err
+		}
+		add(gltf.PropertyTextures, keys)
+	}
+	if opts.Materials {
+		part, err := rewritten(&gltf.Document{Materials: doc.Materials})
+		if err != nil {
+			return err
+		}
+		keys, err := hashObjects(part.Materials, func(m *gltf.Material) ([]byte, error) {
+			c := *m
+			c.Name = ""
+			return json.Marshal(&c)
+		})
+		if err != nil {
+			return err
+		}
+		add(gltf.PropertyMaterials, keys)
+	}
+	if !removed {
+		return nil
+	}
+
+	if err := gltf.RemapDocument(doc, remap); err != nil {
+		return err
+	}
+	doc.Accessors = keepFirst(doc.Accessors, merged[gltf.PropertyAccessors])
+	doc.Images = keepFirst(doc.Images, merged[gltf.PropertyImages])
+	doc.Samplers = keepFirst(doc.Samplers, merged[gltf.PropertySamplers])
+	doc.Textures = keepFirst(doc.Textures, merged[gltf.PropertyTextures])
+	doc.Materials = keepFirst(doc.Materials, merged[gltf.PropertyMaterials])
+	return nil
+}
+
+type dedupKey [sha256.Size]byte
+
+// hashObjects returns the hash of the bytes returned by key for each object.
+// nil objects, and objects for which key returns nil bytes, have a nil hash.
+func hashObjects[T any](objs []*T, key func(*T) ([]byte, error)) ([]*dedupKey, error) {
+	keys := make([]*dedupKey, len(objs))
+	for i, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		b, err := key(obj)
+		if err != nil {
+			return nil, fmt.Errorf("gltf: hashing object %d: %w", i, err)
+		}
+		if b == nil {
+			continue
+		}
+		h := dedupKey(sha256.Sum256(b))
+		keys[i] = &h
+	}
+	return keys, nil
+}
+
+// dedupIndices returns the index of each object once the objects whose key is
+// the one of a previous object are replaced by it, and the number of objects kept.
+// Objects with a nil key are always kept.
+func dedupIndices(keys []*dedupKey) ([]uint32, int) {
+	first := make(map[dedupKey]uint32)
+	indices := make([]uint32, len(keys))
+	var n uint32
+	for i, k := range keys {
+		if k != nil {
+			if j, ok := first[*k]; ok {
+				indices[i] = j
+				continue
+			}
+			first[*k] = n
+		}
+		indices[i] = n
+		n++
+	}
+	return indices, int(n)
+}
+
+// keepFirst removes the objects that indices, as returned by dedupIndices,
+// replaces by a previous object. objs is returned as is if indices is nil.
+func keepFirst[T any](objs []*T, indices []uint32) []*T {
+	if indices == nil {
+		return objs
+	}
+	kept := make([]*T, 0, len(objs))
+	for i, obj := range objs {
+		if int(indices[i]) == len(kept) {
+			kept = append(kept, obj)
+		}
+	}
+	return kept
+}
+
+// accessorData returns the bytes identifying the definition and the data of acr,
+// or nil if its data is not stored in a buffer.
+func accessorData(doc *gltf.Document, acr *gltf.Accessor) ([]byte, error) {
+	if !storedInBuffer(doc, acr) {
+		return nil, nil
+	}
+	var target gltf.Target
+	if acr.BufferView != nil && int(*acr.BufferView) < len(doc.BufferViews) && doc.BufferViews[*acr.BufferView] != nil {
+		target = doc.BufferViews[*acr.BufferView].Target
+	}
+	header, err := json.Marshal([]interface{}{
+		acr.ComponentType, acr.Type, acr.Count, acr.Normalized, acr.Min, acr.Max, target, acr.Extensions, acr.Extras,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, err := ReadAccessor(doc, acr, nil)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, len(header)+int(acr.Count*gltf.SizeOfElement(acr.ComponentType, acr.Type)))
+	copy(b, header)
+	if data != nil {
+		if err := binary.Write(b[len(header):], 0, data); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// imageData returns the bytes identifying the definition and the content of im.
+func imageData(doc *gltf.Document, im *gltf.Image) ([]byte, error) {
+	header, err := json.Marshal([]interface{}{im.MimeType, im.Extensions, im.Extras})
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	switch {
+	case im.BufferView != nil:
+		if int(*im.BufferView) >= len(doc.BufferViews) {
+			return nil, errors.New("gltf: bufferview index overflows")
+		}
+		data, err = ReadBufferView(doc, doc.BufferViews[*im.BufferView])
+		data = append([]byte("bufferView:"), data...)
+	case im.Data != nil:
+		data = append([]byte("data:"), im.Data...)
+	case im.IsEmbeddedResource():
+		data, err = im.MarshalData()
+		data = append([]byte("data:"), data...)
+	default:
+		// The content of the image is not loaded.
+		data = []byte("uri:" + im.URI)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(header, data...), nil
+}
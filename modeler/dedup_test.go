@@ -0,0 +1,133 @@
+package modeler
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/flywave/gltf"
+	"github.com/go-test/deep"
+)
+
+func TestDedup(t *testing.T) {
+	all := DedupOptions{Accessors: true, Images: true, Samplers: true, Textures: true, Materials: true}
+	tests := []struct {
+		name    string
+		opts    DedupOptions
+		modify  func(doc *gltf.Document)
+		wantErr bool
+		want    [5]int // Number of accessors, images, samplers, textures and materials.
+		check   func(t *testing.T, doc *gltf.Document)
+	}{
+		{"all", all, nil, false, [5]int{2, 1, 1, 1, 2}, func(t *testing.T, doc *gltf.Document) {
+			want := []*gltf.Primitive{
+				{Indices: gltf.Index(0), Material: gltf.Index(0)},
+				{Indices: gltf.Index(0), Material: gltf.Index(0)},
+				{Indices: gltf.Index(1), Material: gltf.Index(1)},
+			}
+			if diff := deep.Equal(doc.Meshes[0].Primitives, want); diff != nil {
+				t.Errorf("Dedup() = %v", diff)
+			}
+			if got := doc.Materials[1]; got.Name != "c" || got.PBRMetallicRoughness.BaseColorTexture.Index != 0 {
+				t.Errorf("Dedup() material = %v", got)
+			}
+			if diff := deep.Equal(doc.Textures[0], &gltf.Texture{Source: gltf.Index(0), Sampler: gltf.Index(0)}); diff != nil {
+				t.Errorf("Dedup() texture = %v", diff)
+			}
+		}},
+		{"accessors", DedupOptions{Accessors: true}, nil, false, [5]int{2, 2, 2, 2, 3}, nil},
+		// Without merging images the textures and materials are different.
+		{"noImages", DedupOptions{Samplers: true, Textures: true, Materials: true}, nil, false, [5]int{3, 2, 1, 2, 3}, func(t *testing.T, doc *gltf.Document) {
+			if *doc.Textures[1].Sampler != 0 {
+				t.Errorf("Dedup() texture sampler = %d, want 0", *doc.Textures[1].Sampler)
+			}
+		}},
+		{"loadedImages", all, func(doc *gltf.Document) {
+			doc.Images = []*gltf.Image{{URI: "a.png", Data: []byte{1, 2, 3}}, {URI: "b.png", Data: []byte{1, 2, 3}}}
+		}, false, [5]int{2, 1, 1, 1, 2}, nil},
+		{"differentLoadedImages", all, func(doc *gltf.Document) {
+			doc.Images = []*gltf.Image{{URI: "a.png", Data: []byte{1, 2, 3}}, {URI: "a.png", Data: []byte{4, 5, 6}}}
+		}, false, [5]int{2, 2, 1, 2, 3}, nil},
+		{"readError", all, func(doc *gltf.Document) {
+			doc.Accessors = append(doc.Accessors, &gltf.Accessor{BufferView: gltf.Index(10), Count: 1})
+		}, true, [5]int{4, 2, 2, 2, 3}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gltf.NewDocument()
+			WriteIndices(doc, []uint16{0, 1, 2})
+			WriteIndices(doc, []uint16{0, 1, 2})
+			WriteIndices(doc, []uint16{0, 2, 1})
+			WriteImage(doc, "a", "image/png", bytes.NewReader([]byte{1, 2, 3}))
+			WriteImage(doc, "b", "image/png", bytes.NewReader([]byte{1, 2, 3}))
+			doc.Samplers = []*gltf.Sampler{{Name: "a", WrapS: gltf.WrapClampToEdge}, {Name: "b", WrapS: gltf.WrapClampToEdge}}
+			doc.Textures = []*gltf.Texture{{Source: gltf.Index(0), Sampler: gltf.Index(0)}, {Source: gltf.Index(1), Sampler: gltf.Index(1)}}
+			doc.Materials = []*gltf.Material{
+				{Name: "a", PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 0}}},
+				{Name: "b", PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 1}}},
+				{Name: "c", PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 1}}, DoubleSided: true},
+			}
+			doc.Meshes = []*gltf.Mesh{{Primitives: []*gltf.Primitive{
+				{Indices: gltf.Index(0), Material: gltf.Index(0)},
+				{Indices: gltf.Index(1), Material: gltf.Index(1)},
+				{Indices: gltf.Index(2), Material: gltf.Index(2)},
+			}}}
+			if tt.modify != nil {
+				tt.modify(doc)
+			}
+			if err := DedupWithOptions(doc, tt.opts); (err != nil) != tt.wantErr {
+				t.Fatalf("DedupWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := [5]int{len(doc.Accessors), len(doc.Images), len(doc.Samplers), len(doc.Textures), len(doc.Materials)}
+			if got != tt.want {
+				t.Fatalf("DedupWithOptions() accessors, images, samplers, textures, materials = %v, want %v", got, tt.want)
+			}
+			if tt.check != nil {
+				tt.check(t, doc)
+			}
+		})
+	}
+}
+
+func TestDedup_accessorIdentity(t *testing.T) {
+	doc := gltf.NewDocument()
+	WriteIndices(doc, []uint16{0, 1, 2})
+	WriteAccessor(doc, gltf.TargetArrayBuffer, []uint16{0, 1, 2})
+	bounded := WriteAccessor(doc, gltf.TargetArrayBuffer, []uint16{0, 1, 2})
+	doc.Accessors[bounded].Min, doc.Accessors[bounded].Max = []float32{0}, []float32{2}
+	doc.Accessors = append(doc.Accessors,
+		&gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec3, Count: 3, Min: []float32{-5, -5, -5}, Max: []float32{5, 5, 5}},
+		&gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec3, Count: 3, Min: []float32{-1, -1, -1}, Max: []float32{1, 1, 1}},
+		&gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec3, Count: 3, Min: []float32{-1, -1, -1}, Max: []float32{1, 1, 1}},
+	)
+	if err := DedupWithOptions(doc, DedupOptions{Accessors: true}); err != nil {
+		t.Fatalf("DedupWithOptions() error = %v", err)
+	}
+	if len(doc.Accessors) != 6 {
+		t.Errorf("DedupWithOptions() merged %d accessors, want none", 6-len(doc.Accessors))
+	}
+}
+
+func TestDedup_noPartialUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  gltf.Extensions
+	}{
+		{"marshal error", gltf.Extensions{"bad": make(chan int)}},
+		{"raw payload", gltf.Extensions{"EXT_unknown": json.RawMessage(`{"accessor":1}`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gltf.NewDocument()
+			WriteIndices(doc, []uint16{0, 1, 2})
+			WriteIndices(doc, []uint16{0, 1, 2})
+			doc.Materials = []*gltf.Material{{Name: "a", Extensions: tt.ext}}
+			if err := Dedup(doc); err == nil {
+				t.Error("Dedup() expected error")
+			}
+			if len(doc.Accessors) != 2 {
+				t.Error("Dedup() modified the document")
+			}
+		})
+	}
+}
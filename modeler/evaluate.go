@@ -0,0 +1,270 @@
+package modeler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/flywave/gltf"
+)
+
+// NodePose holds the properties of a node animated at a given time.
+// Properties that are not animated are nil.
+type NodePose struct {
+	Translation *[3]float32
+	Rotation    *[4]float32
+	Scale       *[3]float32
+	Weights     []float32
+}
+
+// AnimationPose maps node indices to their animated properties.
+type AnimationPose map[uint32]*NodePose
+
+// AnimationEvaluator samples the channels of an animation at arbitrary times.
+// The keyframes are read once when the evaluator is created.
+type AnimationEvaluator struct {
+	channels   []*sampledChannel
+	start, end float32
+}
+
+type sampledChannel struct {
+	node          uint32
+	path          gltf.TRSProperty
+	interpolation gltf.Interpolation
+	times         []float32
+	values        []float32 // Flattened keyframe values, including the tangents of cubic splines.
+	size          int       // Number of components of each value.
+}
+
+// NewAnimationEvaluator reads the keyframes of the animation at index in doc.
+// Normalized integer outputs are converted to floats.
+func NewAnimationEvaluator(doc *gltf.Document, index uint32) (*AnimationEvaluator, error) {
+	if int(index) >= len(doc.Animations) {
+		return nil, fmt.Errorf("gltf: animation %d does not exist", index)
+	}
+	anim := doc.Animations[index]
+	e := &AnimationEvaluator{start: float32(math.Inf(1)), end: float32(math.Inf(-1))}
+	for i, c := range anim.Channels {
+		if c.Target.Node == nil {
+			continue
+		}
+		if c.Sampler == nil || int(*c.Sampler) >= len(anim.Samplers) {
+			return nil, fmt.Errorf("gltf: animation channel %d has an invalid sampler", i)
+		}
+		ch, err := readChannel(doc, anim.Samplers[*c.Sampler], *c.Target.Node, c.Target.Path)
+		if err != nil {
+			return nil, fmt.Errorf("gltf: animation channel %d: %w", i, err)
+		}
+		e.channels = append(e.channels, ch)
+		e.start = float32(math.Min(float64(e.start), float64(ch.times[0])))
+		e.end = float32(math.Max(float64(e.end), float64(ch.times[len(ch.times)-1])))
+	}
+	if len(e.channels) == 0 {
+		e.start, e.end = 0, 0
+	}
+	return e, nil
+}
+
+func readChannel(doc *gltf.Document, sampler *gltf.AnimationSampler, node uint32, path gltf.TRSProperty) (*sampledChannel, error) {
+	if int(sampler.Input) >= len(doc.Accessors) || int(sampler.Output) >= len(doc.Accessors) {
+		return nil, fmt.Errorf("sampler accessors do not exist")
+	}
+	times, err := ReadAs[float32](doc, doc.Accessors[sampler.Input], nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("sampler has no keyframes")
+	}
+	output := doc.Accessors[sampler.Output]
+	ch := &sampledChannel{node: node, path: path, interpolation: sampler.Interpolation, times: times}
+	switch path {
+	case gltf.TRSTranslation, gltf.TRSScale:
+		ch.values, err = readFlat[[3]float32](doc, output)
+		ch.size = 3
+	case gltf.TRSRotation:
+		ch.values, err = readFlat[[4]float32](doc, output)
+		ch.size = 4
+	case gltf.TRSWeights:
+		ch.values, err = readFlat[float32](doc, output)
+		ch.size = len(ch.values) / len(times)
+		if sampler.Interpolation == gltf.InterpolationCubicSpline {
+			ch.size /= 3
+		}
+	default:
+		return nil, fmt.Errorf("path %v not supported", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	keys := len(times)
+	if sampler.Interpolation == gltf.InterpolationCubicSpline {
+		keys *= 3
+	}
+	if ch.size == 0 || len(ch.values) != keys*ch.size {
+		return nil, fmt.Errorf("sampler has %d input and %d output values", len(times), len(ch.values))
+	}
+	return ch, nil
+}
+
+// readFlat reads acr as elements of type T and returns their components one after the other.
+func readFlat[T float32 | [3]float32 | [4]float32](doc *gltf.Document, acr *gltf.Accessor) ([]float32, error) {
+	data, err := ReadAs[T](doc, acr, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float32, 0, len(data)*int(acr.Type.Components()))
+	for _, v := range data {
+		switch v := any(v).(type) {
+		case float32:
+			values = append(values, v)
+		case [3]float32:
+			values = append(values, v[:]...)
+		case [4]float32:
+			values = append(values, v[:]...)
+		}
+	}
+	return values, nil
+}
+
+// TimeRange returns the first and last keyframe times of the animation.
+func (e *AnimationEvaluator) TimeRange() (float32, float32) {
+	return e.start, e.end
+}
+
+// Evaluate returns the properties animated at time t.
+// Times out of the range of a channel keyframes are clamped to its first or last keyframe.
+func (e *AnimationEvaluator) Evaluate(t float32) AnimationPose {
+	pose := make(AnimationPose)
+	for _, ch := range e.channels {
+		np, ok := pose[ch.node]
+		if !ok {
+			np = new(NodePose)
+			pose[ch.node] = np
+		}
+		v := ch.sample(t)
+		switch ch.path {
+		case gltf.TRSTranslation:
+			np.Translation = &[3]float32{v[0], v[1], v[2]}
+		case gltf.TRSRotation:
+			np.Rotation = &[4]float32{v[0], v[1], v[2], v[3]}
+		case gltf.TRSScale:
+			np.Scale = &[3]float32{v[0], v[1], v[2]}
+		case gltf.TRSWeights:
+			np.Weights = v
+		}
+	}
+	return pose
+}
+
+// sample returns the channel value at time t.
+func (ch *sampledChannel) sample(t float32) []float32 {
+	// Keyframe values are stored as (in-tangent, value, out-tangent) triplets for cubic splines.
+	stride, offset := ch.size, 0
+	cubic := ch.interpolation == gltf.InterpolationCubicSpline
+	if cubic {
+		stride, offset = 3*ch.size, ch.size
+	}
+	value := func(k, off int) []float32 {
+		return ch.values[k*stride+off : k*stride+off+ch.size]
+	}
+	out := make([]float32, ch.size)
+	last := len(ch.times) - 1
+	k := sort.Search(len(ch.times), func(i int) bool { return ch.times[i] > t }) - 1
+	if k < 0 || k >= last {
+		copy(out, value(max(0, min(k, last)), offset))
+		return out
+	}
+	dt := ch.times[k+1] - ch.times[k]
+	s := (t - ch.times[k]) / dt
+	switch {
+	case ch.interpolation == gltf.InterpolationStep:
+		copy(out, value(k, offset))
+	case cubic:
+		s2, s3 := s*s, s*s*s
+		v0, b0 := value(k, offset), value(k, 2*ch.size)
+		v1, a1 := value(k+1, offset), value(k+1, 0)
+		for i := range out {
+			out[i] = (2*s3-3*s2+1)*v0[i] + dt*(s3-2*s2+s)*b0[i] + (-2*s3+3*s2)*v1[i] + dt*(s3-s2)*a1[i]
+		}
+		if ch.path == gltf.TRSRotation {
+			normalizeQuat(out)
+		}
+	case ch.path == gltf.TRSRotation:
+		slerp(out, value(k, 0), value(k+1, 0), s)
+	default:
+		v0, v1 := value(k, 0), value(k+1, 0)
+		for i := range out {
+			out[i] = v0[i] + s*(v1[i]-v0[i])
+		}
+	}
+	return out
+}
+
+// slerp stores in dst the spherical interpolation between the quaternions a and b,
+// following the shortest path.
+func slerp(dst, a, b []float32, s float32) {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	sign := 1.0
+	if dot < 0 {
+		dot, sign = -dot, -1
+	}
+	wa, wb := 1-float64(s), float64(s)
+	if dot < 0.9995 {
+		theta := math.Acos(dot)
+		sin := math.Sin(theta)
+		wa, wb = math.Sin((1-float64(s))*theta)/sin, math.Sin(float64(s)*theta)/sin
+	}
+	for i := range dst {
+		dst[i] = float32(wa*float64(a[i]) + sign*wb*float64(b[i]))
+	}
+	normalizeQuat(dst)
+}
+
+func normalizeQuat(q []float32) {
+	var l float64
+	for _, v := range q {
+		l += float64(v) * float64(v)
+	}
+	if l == 0 {
+		return
+	}
+	l = math.Sqrt(l)
+	for i := range q {
+		q[i] = float32(float64(q[i]) / l)
+	}
+}
+
+// ApplyPose returns a copy of doc where the nodes have the properties of pose.
+// Animated nodes defined by a matrix are converted to the TRS form.
+func ApplyPose(doc *gltf.Document, pose AnimationPose) (*gltf.Document, error) {
+	c := doc.Clone()
+	for index, np := range pose {
+		if int(index) >= len(c.Nodes) {
+			continue
+		}
+		n := c.Nodes[index]
+		animated := np.Translation != nil || np.Rotation != nil || np.Scale != nil
+		if animated && n.MatrixOrDefault() != gltf.DefaultMatrix {
+			if err := n.UseTRS(); err != nil {
+				return nil, fmt.Errorf("gltf: node %d: %w", index, err)
+			}
+		}
+		if np.Translation != nil {
+			n.Translation = *np.Translation
+		}
+		if np.Rotation != nil {
+			n.Rotation = *np.Rotation
+		}
+		if np.Scale != nil {
+			n.Scale = *np.Scale
+		}
+		if np.Weights != nil {
+			n.Weights = append([]float32(nil), np.Weights...)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,118 @@
+package modeler
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flywave/gltf"
+	"github.com/go-test/deep"
+)
+
+func TestAnimationEvaluator(t *testing.T) {
+	doc := gltf.NewDocument()
+	doc.Nodes = []*gltf.Node{{}, {Matrix: [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 5, 0, 0, 1}}, {}}
+	s := float32(math.Sqrt2 / 2)
+	b := NewAnimationBuilder(doc, "")
+	for _, err := range []error{
+		b.Translation(0, []float32{1, 3}, [][3]float32{{0, 0, 0}, {4, 2, 0}}, gltf.InterpolationLinear),
+		b.Rotation(0, []float32{0, 1}, [][4]float32{{0, 0, 0, 1}, {0, 0, s, s}}, gltf.InterpolationLinear),
+		b.Scale(0, []float32{0, 1}, [][3]float32{{1, 1, 1}, {2, 2, 2}}, gltf.InterpolationStep),
+		b.Translation(1, []float32{0, 2}, [][3]float32{
+			{0, 0, 0}, {0, 0, 0}, {0, 0, 0},
+			{0, 0, 0}, {0, 8, 0}, {0, 0, 0},
+		}, gltf.InterpolationCubicSpline),
+		b.Weights(2, []float32{0, 1}, [][]float32{{0, 1}, {1, 0}}, gltf.InterpolationLinear),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := NewAnimationEvaluator(doc, 0)
+	if err != nil {
+		t.Fatalf("NewAnimationEvaluator() error = %v", err)
+	}
+	if start, end := e.TimeRange(); start != 0 || end != 3 {
+		t.Errorf("TimeRange() = %v, %v", start, end)
+	}
+	sin, cos := float32(math.Sin(math.Pi/8)), float32(math.Cos(math.Pi/8))
+	tests := []struct {
+		name string
+		t    float32
+		want AnimationPose
+	}{
+		{"start", 0, AnimationPose{
+			0: {Translation: &[3]float32{0, 0, 0}, Rotation: &[4]float32{0, 0, 0, 1}, Scale: &[3]float32{1, 1, 1}},
+			1: {Translation: &[3]float32{0, 0, 0}},
+			2: {Weights: []float32{0, 1}},
+		}},
+		{"middle", 0.5, AnimationPose{
+			0: {Translation: &[3]float32{0, 0, 0}, Rotation: &[4]float32{0, 0, sin, cos}, Scale: &[3]float32{1, 1, 1}},
+			1: {Translation: &[3]float32{0, 1.25, 0}},
+			2: {Weights: []float32{0.5, 0.5}},
+		}},
+		{"end", 2, AnimationPose{
+			0: {Translation: &[3]float32{2, 1, 0}, Rotation: &[4]float32{0, 0, s, s}, Scale: &[3]float32{2, 2, 2}},
+			1: {Translation: &[3]float32{0, 8, 0}},
+			2: {Weights: []float32{1, 0}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deep.FloatPrecision = 5
+			defer func() { deep.FloatPrecision = 10 }()
+			if diff := deep.Equal(e.Evaluate(tt.t), tt.want); diff != nil {
+				t.Errorf("Evaluate() = %v", diff)
+			}
+		})
+	}
+
+	posed, err := ApplyPose(doc, e.Evaluate(2))
+	if err != nil {
+		t.Fatalf("ApplyPose() error = %v", err)
+	}
+	if posed.Nodes[0].Translation != [3]float32{2, 1, 0} || posed.Nodes[0].Scale != [3]float32{2, 2, 2} {
+		t.Errorf("ApplyPose() node 0 = %+v", posed.Nodes[0])
+	}
+	if n := posed.Nodes[1]; n.Matrix != gltf.DefaultMatrix || n.Translation != [3]float32{0, 8, 0} {
+		t.Errorf("ApplyPose() node 1 = %+v", n)
+	}
+	if diff := deep.Equal(posed.Nodes[2].Weights, []float32{1, 0}); diff != nil {
+		t.Errorf("ApplyPose() weights = %v", diff)
+	}
+	if doc.Nodes[0].Translation != [3]float32{} || doc.Nodes[1].Matrix[12] != 5 {
+		t.Error("ApplyPose() modified the original document")
+	}
+
+	if _, err := NewAnimationEvaluator(doc, 1); err == nil {
+		t.Error("NewAnimationEvaluator() expected error for a missing animation")
+	}
+	doc.Animations[0].Samplers[0].Output = doc.Animations[0].Samplers[1].Input
+	if _, err := NewAnimationEvaluator(doc, 0); err == nil {
+		t.Error("NewAnimationEvaluator() expected error for mismatched output")
+	}
+}
+
+func TestAnimationEvaluator_NormalizedRotation(t *testing.T) {
+	doc := gltf.NewDocument()
+	doc.Nodes = []*gltf.Node{{}}
+	input := WriteAccessor(doc, gltf.TargetNone, []float32{0, 1})
+	output := WriteAccessor(doc, gltf.TargetNone, [][4]int16{{0, 0, 0, 32767}, {0, 0, 32767, 0}})
+	doc.Accessors[output].Normalized = true
+	doc.Animations = []*gltf.Animation{{
+		Samplers: []*gltf.AnimationSampler{{Input: input, Output: output}},
+		Channels: []*gltf.Channel{{Sampler: gltf.Index(0), Target: gltf.ChannelTarget{Node: gltf.Index(0), Path: gltf.TRSRotation}}},
+	}}
+	e, err := NewAnimationEvaluator(doc, 0)
+	if err != nil {
+		t.Fatalf("NewAnimationEvaluator() error = %v", err)
+	}
+	got := *e.Evaluate(0.5)[0].Rotation
+	s := float32(math.Sqrt2 / 2)
+	if math.Abs(float64(got[2]-s)) > 1e-6 || math.Abs(float64(got[3]-s)) > 1e-6 {
+		t.Errorf("Evaluate() rotation = %v", got)
+	}
+}
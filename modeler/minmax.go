@@ -0,0 +1,163 @@
+package modeler
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+
+	"github.com/flywave/gltf"
+)
+
+// ComputeMinMax returns the minimum and maximum value of each component of the accessor data,
+// as read by ReadAccessor, so sparse substitution is already applied.
+//
+// As required by the specification, the values of normalized accessors are not normalized:
+// the bounds of a normalized ubyte accessor are in the [0, 255] range.
+// Matrix components are returned in column-major order.
+// It returns nil slices if acr is empty, and an error if its data is not stored
+// in a buffer view that can be read, see RepairMinMax.
+func ComputeMinMax(doc *gltf.Document, acr *gltf.Accessor) ([]float32, []float32, error) {
+	if acr.Count == 0 {
+		return nil, nil, nil
+	}
+	if !storedInBuffer(doc, acr) {
+		return nil, nil, fmt.Errorf("gltf: accessor data is not stored in a buffer")
+	}
+	n := int(acr.Type.Components())
+	data, err := ReadAccessor(doc, acr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	min := make([]float32, n)
+	max := make([]float32, n)
+	for i := range min {
+		min[i] = math.MaxFloat32
+		max[i] = -math.MaxFloat32
+	}
+	values := make([]float64, 0, n)
+	v := reflect.ValueOf(data)
+	for i := 0; i < v.Len(); i++ {
+		values = appendComponents(values[:0], v.Index(i))
+		for j, x := range values {
+			min[j] = float32(math.Min(float64(min[j]), x))
+			max[j] = float32(math.Max(float64(max[j]), x))
+		}
+	}
+	return min, max, nil
+}
+
+// storedInBuffer reports whether the data of acr can be read from its buffer views.
+// It is not the case when acr has neither buffer view nor sparse storage,
+// as its data is usually provided by a compression extension of the primitive,
+// such as KHR_draco_mesh_compression, nor when its buffer view has extensions,
+// such as EXT_meshopt_compression, which may store the data in another form.
+func storedInBuffer(doc *gltf.Document, acr *gltf.Accessor) bool {
+	if acr.BufferView == nil {
+		return acr.Sparse != nil
+	}
+	if int(*acr.BufferView) < len(doc.BufferViews) {
+		if bv := doc.BufferViews[*acr.BufferView]; bv != nil && len(bv.Extensions) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// appendComponents appends to dst the components of v, flattening nested arrays.
+func appendComponents(dst []float64, v reflect.Value) []float64 {
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			dst = appendComponents(dst, v.Index(i))
+		}
+	case reflect.Float32:
+		dst = append(dst, v.Float())
+	case reflect.Int8, reflect.Int16:
+		dst = append(dst, float64(v.Int()))
+	default:
+		dst = append(dst, float64(v.Uint()))
+	}
+	return dst
+}
+
+// UpdateMinMax recomputes the Min and Max of acr with ComputeMinMax
+// and reports whether they were different from the stored values.
+func UpdateMinMax(doc *gltf.Document, acr *gltf.Accessor) (bool, error) {
+	min, max, err := ComputeMinMax(doc, acr)
+	if err != nil {
+		return false, err
+	}
+	if slices.Equal(min, acr.Min) && slices.Equal(max, acr.Max) {
+		return false, nil
+	}
+	acr.Min, acr.Max = min, max
+	return true, nil
+}
+
+// MinMaxReport lists the accessors modified by RepairMinMax.
+type MinMaxReport struct {
+	// Updated contains the accessors whose stale Min or Max have been corrected.
+	Updated []uint32
+	// Missing contains the accessors that the specification requires to have Min and Max,
+	// such as POSITION attributes and animation sampler inputs, but had none.
+	// Their bounds have been computed.
+	Missing []uint32
+}
+
+// RepairMinMax recomputes the Min and Max of the accessors that define them
+// and of the accessors that are required to define them, correcting them in place.
+// Accessors whose data is not stored in a buffer, such as the ones decoded
+// by KHR_draco_mesh_compression, are skipped as their bounds can't be computed.
+//
+// The document is not modified if any accessor cannot be read.
+func RepairMinMax(doc *gltf.Document) (*MinMaxReport, error) {
+	required := make(map[uint32]bool)
+	for _, mesh := range doc.Meshes {
+		for _, p := range mesh.Primitives {
+			if index, ok := p.Attributes[gltf.POSITION]; ok {
+				required[index] = true
+			}
+			for _, target := range p.Targets {
+				if index, ok := target[gltf.POSITION]; ok {
+					required[index] = true
+				}
+			}
+		}
+	}
+	for _, anim := range doc.Animations {
+		for _, sampler := range anim.Samplers {
+			required[sampler.Input] = true
+		}
+	}
+
+	type bounds struct{ min, max []float32 }
+	computed := make(map[uint32]bounds)
+	for i, acr := range doc.Accessors {
+		index := uint32(i)
+		if (!required[index] && acr.Min == nil && acr.Max == nil) || !storedInBuffer(doc, acr) {
+			continue
+		}
+		min, max, err := ComputeMinMax(doc, acr)
+		if err != nil {
+			return nil, fmt.Errorf("gltf: accessor %d: %w", index, err)
+		}
+		computed[index] = bounds{min, max}
+	}
+
+	report := new(MinMaxReport)
+	for i, acr := range doc.Accessors {
+		index := uint32(i)
+		b, ok := computed[index]
+		if !ok || b.min == nil || (slices.Equal(b.min, acr.Min) && slices.Equal(b.max, acr.Max)) {
+			continue
+		}
+		if acr.Min == nil || acr.Max == nil {
+			report.Missing = append(report.Missing, index)
+		} else {
+			report.Updated = append(report.Updated, index)
+		}
+		acr.Min, acr.Max = b.min, b.max
+	}
+	return report, nil
+}
@@ -0,0 +1,131 @@
+package modeler
+
+import (
+	"testing"
+
+	"github.com/flywave/gltf"
+	"github.com/go-test/deep"
+)
+
+func TestComputeMinMax(t *testing.T) {
+	doc := gltf.NewDocument()
+	vec3 := WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{1, -2, 3}, {-1, 2, 0}})
+	normalized := WriteAccessor(doc, gltf.TargetArrayBuffer, [][2]uint8{{255, 0}, {10, 20}})
+	doc.Accessors[normalized].Normalized = true
+	mat2 := WriteAccessor(doc, gltf.TargetNone, [][2][2]int8{{{1, 2}, {3, 4}}, {{-1, 5}, {0, 0}}})
+	sparse := WriteAccessor(doc, gltf.TargetNone, []uint16{1, 2, 3})
+	indices := WriteBufferView(doc, gltf.TargetNone, []uint8{1})
+	values := WriteBufferView(doc, gltf.TargetNone, []uint16{9})
+	doc.Accessors[sparse].Sparse = &gltf.Sparse{
+		Count:   1,
+		Indices: gltf.SparseIndices{BufferView: indices, ComponentType: gltf.ComponentUbyte},
+		Values:  gltf.SparseValues{BufferView: values},
+	}
+	doc.Accessors = append(doc.Accessors,
+		&gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec2, Count: 2},
+		&gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorScalar},
+		&gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorScalar, Count: 1, BufferView: gltf.Index(10)},
+	)
+	tests := []struct {
+		name     string
+		index    int
+		min, max []float32
+		wantErr  bool
+	}{
+		{"vec3", int(vec3), []float32{-1, -2, 0}, []float32{1, 2, 3}, false},
+		{"normalized", int(normalized), []float32{10, 0}, []float32{255, 20}, false},
+		{"mat2", int(mat2), []float32{-1, 2, 0, 0}, []float32{1, 5, 3, 4}, false},
+		{"sparse", int(sparse), []float32{1}, []float32{9}, false},
+		{"no buffer view", 4, nil, nil, true},
+		{"empty", 5, nil, nil, false},
+		{"invalid", 6, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			min, max, err := ComputeMinMax(doc, doc.Accessors[tt.index])
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ComputeMinMax() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(min, tt.min); diff != nil {
+				t.Errorf("ComputeMinMax() min = %v", diff)
+			}
+			if diff := deep.Equal(max, tt.max); diff != nil {
+				t.Errorf("ComputeMinMax() max = %v", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateMinMax(t *testing.T) {
+	doc := gltf.NewDocument()
+	index := WritePosition(doc, [][3]float32{{1, 2, 3}})
+	acr := doc.Accessors[index]
+	if changed, err := UpdateMinMax(doc, acr); err != nil || changed {
+		t.Errorf("UpdateMinMax() = %v, %v, want false", changed, err)
+	}
+	acr.Max = []float32{0, 0, 0}
+	if changed, err := UpdateMinMax(doc, acr); err != nil || !changed {
+		t.Errorf("UpdateMinMax() = %v, %v, want true", changed, err)
+	}
+	if diff := deep.Equal(acr.Max, []float32{1, 2, 3}); diff != nil {
+		t.Errorf("UpdateMinMax() max = %v", diff)
+	}
+}
+
+func TestRepairMinMax(t *testing.T) {
+	doc := gltf.NewDocument()
+	position := WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{1, 2, 3}, {0, 0, 0}})
+	target := WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{0, 1, 0}, {0, 0, 0}})
+	stale := WriteAccessor(doc, gltf.TargetArrayBuffer, [][2]float32{{1, 1}, {0, 0}})
+	doc.Accessors[stale].Min = []float32{0, 0}
+	doc.Accessors[stale].Max = []float32{2, 2}
+	input := WriteAccessor(doc, gltf.TargetNone, []float32{0, 1})
+	other := WriteAccessor(doc, gltf.TargetNone, []float32{5})
+	doc.Meshes = []*gltf.Mesh{{Primitives: []*gltf.Primitive{{
+		Attributes: gltf.Attribute{gltf.POSITION: position, gltf.TEXCOORD_0: stale},
+		Targets:    []gltf.Attribute{{gltf.POSITION: target}},
+	}}}}
+	doc.Animations = []*gltf.Animation{{Samplers: []*gltf.AnimationSampler{{Input: input, Output: other}}}}
+
+	report, err := RepairMinMax(doc)
+	if err != nil {
+		t.Fatalf("RepairMinMax() error = %v", err)
+	}
+	if diff := deep.Equal(report, &MinMaxReport{Updated: []uint32{stale}, Missing: []uint32{position, target, input}}); diff != nil {
+		t.Errorf("RepairMinMax() report = %v", diff)
+	}
+	if diff := deep.Equal(doc.Accessors[stale].Max, []float32{1, 1}); diff != nil {
+		t.Errorf("RepairMinMax() max = %v", diff)
+	}
+	if diff := deep.Equal(doc.Accessors[input].Min, []float32{0}); diff != nil {
+		t.Errorf("RepairMinMax() min = %v", diff)
+	}
+	if doc.Accessors[other].Min != nil {
+		t.Error("RepairMinMax() expected optional bounds to be left unset")
+	}
+
+	doc.Accessors = append(doc.Accessors, &gltf.Accessor{
+		ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec3, Count: 3,
+		Min: []float32{-5, -5, -5}, Max: []float32{5, 5, 5},
+	})
+	draco := uint32(len(doc.Accessors) - 1)
+	doc.Meshes[0].Primitives = append(doc.Meshes[0].Primitives, &gltf.Primitive{
+		Attributes: gltf.Attribute{gltf.POSITION: draco},
+		Extensions: gltf.Extensions{"KHR_draco_mesh_compression": map[string]interface{}{"bufferView": 0}},
+	})
+	if report, err = RepairMinMax(doc); err != nil || len(report.Updated)+len(report.Missing) != 0 {
+		t.Errorf("RepairMinMax() = %v, %v, want empty report", report, err)
+	}
+	if diff := deep.Equal(doc.Accessors[draco].Max, []float32{5, 5, 5}); diff != nil {
+		t.Errorf("RepairMinMax() compressed accessor max = %v", diff)
+	}
+
+	doc.Accessors[stale].Max = nil
+	doc.Accessors[position].BufferView = gltf.Index(100)
+	if _, err := RepairMinMax(doc); err == nil {
+		t.Error("RepairMinMax() expected error")
+	}
+	if doc.Accessors[stale].Max != nil {
+		t.Error("RepairMinMax() modified the document on error")
+	}
+}
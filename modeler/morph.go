@@ -0,0 +1,127 @@
+package modeler
+
+import (
+	"fmt"
+
+	"github.com/flywave/gltf"
+)
+
+// MorphTarget defines the attribute displacements of a morph target.
+// Each non empty slice must have as many elements as the matching primitive attribute.
+type MorphTarget struct {
+	// Name is recorded in the mesh extras as targetNames, following the common convention.
+	Name string
+	// Weight is the default weight of the target.
+	Weight   float32
+	Position [][3]float32
+	Normal   [][3]float32
+	Tangent  [][3]float32
+	// Sparse stores the displacements as sparse accessors
+	// so only the non-zero ones are written to the buffer.
+	Sparse bool
+}
+
+// WriteMorphTarget adds target to the primitive p of mesh and fills the last buffer with its displacements.
+// The first primitive that receives a target at a given index also appends its weight
+// to mesh.Weights and its name to mesh.Extras["targetNames"];
+// the other primitives must use the same name for that index.
+// If success it returns the index of the new target.
+func WriteMorphTarget(doc *gltf.Document, mesh *gltf.Mesh, p *gltf.Primitive, target MorphTarget) (int, error) {
+	deltas := []struct {
+		name string
+		data [][3]float32
+	}{{gltf.POSITION, target.Position}, {gltf.NORMAL, target.Normal}, {gltf.TANGENT, target.Tangent}}
+	empty := true
+	for _, d := range deltas {
+		if len(d.data) == 0 {
+			continue
+		}
+		empty = false
+		index, ok := p.Attributes[d.name]
+		if !ok || int(index) >= len(doc.Accessors) {
+			return 0, fmt.Errorf("gltf: morph target %s has no base attribute", d.name)
+		}
+		if count := doc.Accessors[index].Count; uint32(len(d.data)) != count {
+			return 0, fmt.Errorf("gltf: morph target %s has %d elements, want %d", d.name, len(d.data), count)
+		}
+	}
+	if empty {
+		return 0, fmt.Errorf("gltf: morph target %q has no displacements", target.Name)
+	}
+	index := len(p.Targets)
+	names, err := targetNames(mesh)
+	if err != nil {
+		return 0, err
+	}
+	if index < len(mesh.Weights) && index < len(names) && names[index] != target.Name {
+		return 0, fmt.Errorf("gltf: morph target %d is named %q in other primitives", index, names[index])
+	}
+
+	attrs := make(gltf.Attribute)
+	for _, d := range deltas {
+		if len(d.data) == 0 {
+			continue
+		}
+		var acr uint32
+		if target.Sparse {
+			if acr, err = WriteSparseAccessorDiff(doc, nil, d.data); err != nil {
+				return 0, err
+			}
+		} else {
+			acr = WriteAccessor(doc, gltf.TargetArrayBuffer, d.data)
+		}
+		if d.name == gltf.POSITION {
+			if a := doc.Accessors[acr]; a.BufferView == nil && a.Sparse == nil {
+				// A sparse target with no displacement has no storage to read.
+				a.Min, a.Max = make([]float32, 3), make([]float32, 3)
+			} else if _, err := UpdateMinMax(doc, a); err != nil {
+				return 0, err
+			}
+		}
+		attrs[d.name] = acr
+	}
+	p.Targets = append(p.Targets, attrs)
+
+	if index >= len(mesh.Weights) {
+		mesh.Weights = append(mesh.Weights, make([]float32, index-len(mesh.Weights))...)
+		mesh.Weights = append(mesh.Weights, target.Weight)
+		if target.Name != "" || len(names) > 0 {
+			names = append(names, make([]string, max(0, index-len(names)))...)
+			setTargetNames(mesh, append(names[:index], target.Name))
+		}
+	}
+	return index, nil
+}
+
+// targetNames returns the target names recorded in the mesh extras.
+func targetNames(mesh *gltf.Mesh) ([]string, error) {
+	if mesh.Extras == nil {
+		return nil, nil
+	}
+	extras, ok := mesh.Extras.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("gltf: mesh extras of type %T cannot hold targetNames", mesh.Extras)
+	}
+	switch v := extras["targetNames"].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		names := make([]string, len(v))
+		for i, name := range v {
+			names[i], _ = name.(string)
+		}
+		return names, nil
+	}
+	return nil, fmt.Errorf("gltf: mesh targetNames of type %T not supported", extras["targetNames"])
+}
+
+func setTargetNames(mesh *gltf.Mesh, names []string) {
+	extras, _ := mesh.Extras.(map[string]interface{})
+	if extras == nil {
+		extras = make(map[string]interface{})
+		mesh.Extras = extras
+	}
+	extras["targetNames"] = names
+}
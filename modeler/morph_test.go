@@ -0,0 +1,99 @@
+package modeler
+
+import (
+	"testing"
+
+	"github.com/flywave/gltf"
+	"github.com/go-test/deep"
+)
+
+func TestWriteMorphTarget(t *testing.T) {
+	doc := gltf.NewDocument()
+	position := WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+	normal := WriteNormal(doc, [][3]float32{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}})
+	p1 := &gltf.Primitive{Attributes: gltf.Attribute{gltf.POSITION: position, gltf.NORMAL: normal}}
+	p2 := &gltf.Primitive{Attributes: gltf.Attribute{gltf.POSITION: position}}
+	mesh := &gltf.Mesh{Primitives: []*gltf.Primitive{p1, p2}}
+
+	index, err := WriteMorphTarget(doc, mesh, p1, MorphTarget{
+		Name:     "smile",
+		Weight:   0.5,
+		Position: [][3]float32{{0, 0, 0}, {0, 0, 2}, {0, 0, 0}},
+		Normal:   [][3]float32{{0, 1, 0}, {0, 1, 0}, {0, 1, 0}},
+	})
+	if err != nil || index != 0 {
+		t.Fatalf("WriteMorphTarget() = %v, %v", index, err)
+	}
+	acr := doc.Accessors[p1.Targets[0][gltf.POSITION]]
+	if acr.Sparse != nil || deep.Equal(acr.Min, []float32{0, 0, 0}) != nil || deep.Equal(acr.Max, []float32{0, 0, 2}) != nil {
+		t.Errorf("WriteMorphTarget() position accessor = %+v", acr)
+	}
+	if _, ok := p1.Targets[0][gltf.NORMAL]; !ok {
+		t.Error("WriteMorphTarget() expected a NORMAL target")
+	}
+
+	index, err = WriteMorphTarget(doc, mesh, p1, MorphTarget{
+		Name:     "blink",
+		Position: [][3]float32{{0, 0, 0}, {0, 0, 0}, {0, 3, 0}},
+		Sparse:   true,
+	})
+	if err != nil || index != 1 {
+		t.Fatalf("WriteMorphTarget() = %v, %v", index, err)
+	}
+	acr = doc.Accessors[p1.Targets[1][gltf.POSITION]]
+	if acr.Sparse == nil || acr.Sparse.Count != 1 || deep.Equal(acr.Max, []float32{0, 3, 0}) != nil {
+		t.Errorf("WriteMorphTarget() sparse position accessor = %+v", acr)
+	}
+
+	if _, err = WriteMorphTarget(doc, mesh, p2, MorphTarget{Name: "smile", Position: make([][3]float32, 3), Sparse: true}); err != nil {
+		t.Fatalf("WriteMorphTarget() error = %v", err)
+	}
+	acr = doc.Accessors[p2.Targets[0][gltf.POSITION]]
+	if acr.BufferView != nil || acr.Sparse != nil || deep.Equal(acr.Max, []float32{0, 0, 0}) != nil {
+		t.Errorf("WriteMorphTarget() empty sparse position accessor = %+v", acr)
+	}
+	if diff := deep.Equal(mesh.Weights, []float32{0.5, 0}); diff != nil {
+		t.Errorf("WriteMorphTarget() weights = %v", diff)
+	}
+	if diff := deep.Equal(mesh.Extras, map[string]interface{}{"targetNames": []string{"smile", "blink"}}); diff != nil {
+		t.Errorf("WriteMorphTarget() extras = %v", diff)
+	}
+}
+
+func TestWriteMorphTarget_Error(t *testing.T) {
+	doc := gltf.NewDocument()
+	position := WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}})
+	newMesh := func() (*gltf.Mesh, *gltf.Primitive) {
+		p := &gltf.Primitive{Attributes: gltf.Attribute{gltf.POSITION: position}}
+		return &gltf.Mesh{Primitives: []*gltf.Primitive{p}}, p
+	}
+	tests := []struct {
+		name   string
+		extras interface{}
+		target MorphTarget
+	}{
+		{"empty", nil, MorphTarget{}},
+		{"count mismatch", nil, MorphTarget{Position: make([][3]float32, 3)}},
+		{"missing base", nil, MorphTarget{Normal: make([][3]float32, 2)}},
+		{"invalid extras", "extras", MorphTarget{Position: make([][3]float32, 2)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh, p := newMesh()
+			mesh.Extras = tt.extras
+			if _, err := WriteMorphTarget(doc, mesh, p, tt.target); err == nil {
+				t.Error("WriteMorphTarget() expected error")
+			}
+			if len(p.Targets) != 0 || len(mesh.Weights) != 0 {
+				t.Error("WriteMorphTarget() modified the mesh on error")
+			}
+		})
+	}
+
+	mesh, p := newMesh()
+	mesh.Extras = map[string]interface{}{"targetNames": []interface{}{"a"}}
+	mesh.Weights = []float32{0}
+	if _, err := WriteMorphTarget(doc, mesh, p, MorphTarget{Name: "b", Position: make([][3]float32, 2)}); err == nil {
+		t.Error("WriteMorphTarget() expected name mismatch error")
+	}
+}
@@ -28,37 +28,35 @@ func ReadAccessor(doc *gltf.Document, acr *gltf.Accessor, buffer interface{}) (i
 	}
 	buffer = binary.MakeSliceBuffer(acr.ComponentType, acr.Type, acr.Count, buffer)
 	if acr.BufferView != nil {
-		buf, err := readBufferView(doc, *acr.BufferView)
+		buf, byteStride, err := readAccessorData(doc, *acr.BufferView, acr.ByteOffset, acr.Count, gltf.SizeOfElement(acr.ComponentType, acr.Type))
 		if err != nil {
 			return nil, err
 		}
-		byteStride := doc.BufferViews[*acr.BufferView].ByteStride
-		err = binary.Read(buf[acr.ByteOffset:], byteStride, buffer)
+		err = binary.Read(buf, byteStride, buffer)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if acr.Sparse != nil {
-		indicesBuffer, err := readBufferView(doc, acr.Sparse.Indices.BufferView)
+		indicesBuffer, byteStride, err := readAccessorData(doc, acr.Sparse.Indices.BufferView, acr.Sparse.Indices.ByteOffset,
+			acr.Sparse.Count, gltf.SizeOfElement(acr.Sparse.Indices.ComponentType, gltf.AccessorScalar))
 		if err != nil {
 			return nil, err
 		}
-
-		byteStride := doc.BufferViews[acr.Sparse.Indices.BufferView].ByteStride
 		indices := binary.MakeSlice(acr.Sparse.Indices.ComponentType, gltf.AccessorScalar, acr.Sparse.Count)
-		err = binary.Read(indicesBuffer[acr.Sparse.Indices.ByteOffset:], byteStride, indices)
+		err = binary.Read(indicesBuffer, byteStride, indices)
 		if err != nil {
 			return nil, err
 		}
 
-		valuesBuffer, err := readBufferView(doc, acr.Sparse.Values.BufferView)
+		valuesBuffer, byteStride, err := readAccessorData(doc, acr.Sparse.Values.BufferView, acr.Sparse.Values.ByteOffset,
+			acr.Sparse.Count, gltf.SizeOfElement(acr.ComponentType, acr.Type))
 		if err != nil {
 			return nil, err
 		}
-		byteStride = doc.BufferViews[acr.Sparse.Values.ByteOffset].ByteStride
 		values := binary.MakeSlice(acr.ComponentType, acr.Type, acr.Sparse.Count)
-		err = binary.Read(valuesBuffer[acr.Sparse.Values.ByteOffset:], byteStride, values)
+		err = binary.Read(valuesBuffer, byteStride, values)
 		if err != nil {
 			return nil, err
 		}
@@ -75,11 +73,30 @@ func ReadAccessor(doc *gltf.Document, acr *gltf.Accessor, buffer interface{}) (i
 	return buffer, nil
 }
 
-func readBufferView(doc *gltf.Document, bufferViewIndex uint32) ([]byte, error) {
+// readAccessorData returns the bytes of the buffer view that hold count elements
+// of elementSize bytes starting at byteOffset, together with the buffer view stride.
+// Only those bytes are read when the buffer is not loaded in memory.
+func readAccessorData(doc *gltf.Document, bufferViewIndex, byteOffset, count, elementSize uint32) ([]byte, uint32, error) {
 	if uint32(len(doc.BufferViews)) <= bufferViewIndex {
-		return nil, errors.New("gltf: bufferview index overflows")
+		return nil, 0, errors.New("gltf: bufferview index overflows")
+	}
+	bv := doc.BufferViews[bufferViewIndex]
+	if uint32(len(doc.Buffers)) <= bv.Buffer {
+		return nil, 0, errors.New("gltf: buffer index overflows")
+	}
+	var length uint64
+	if count > 0 {
+		step := uint64(elementSize)
+		if bv.ByteStride != 0 {
+			step = uint64(bv.ByteStride)
+		}
+		length = step*uint64(count-1) + uint64(elementSize)
+	}
+	if uint64(byteOffset)+length > uint64(bv.ByteLength) {
+		return nil, 0, io.ErrShortBuffer
 	}
-	return ReadBufferView(doc, doc.BufferViews[bufferViewIndex])
+	buf, err := doc.Buffers[bv.Buffer].ReadRange(bv.ByteOffset+byteOffset, uint32(length))
+	return buf, bv.ByteStride, err
 }
 
 // ReadBufferView returns the slice of bytes associated with the BufferView.
@@ -90,13 +107,7 @@ func ReadBufferView(doc *gltf.Document, bv *gltf.BufferView) ([]byte, error) {
 	if uint32(len(doc.Buffers)) <= bv.Buffer {
 		return nil, errors.New("gltf: buffer index overflows")
 	}
-	buf := doc.Buffers[bv.Buffer].Data
-
-	high := bv.ByteOffset + bv.ByteLength
-	if uint32(len(buf)) < high {
-		return nil, io.ErrShortBuffer
-	}
-	return buf[bv.ByteOffset:high], nil
+	return doc.Buffers[bv.Buffer].ReadRange(bv.ByteOffset, bv.ByteLength)
 }
 
 // ReadIndices returns the data referenced by acr.
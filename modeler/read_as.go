@@ -0,0 +1,144 @@
+package modeler
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"unsafe"
+
+	"github.com/flywave/gltf"
+	"github.com/flywave/gltf/binary"
+)
+
+// ReadAs returns the data referenced by acr converted to elements of type T.
+// T must be a numeric type or a, possibly nested, array of a numeric type
+// with as many components as acr.Type, such as float32 for scalars, [3]float32 for VEC3,
+// [4]uint16 for VEC4 or [4][4]float32 for MAT4. Matrices are stored in column-major order.
+//
+// Components are converted as follows:
+//   - Normalized components are denormalized when T is a float type
+//     and rescaled to the full range of the integer type otherwise.
+//   - Other components are converted by value, rounding and clamping floats
+//     when T is an integer type.
+//
+// If buffer has enough capacity it will be used as backing slice, else a new slice will be allocated.
+//
+// See ReadAccessor for more info.
+func ReadAs[T any](doc *gltf.Document, acr *gltf.Accessor, buffer []T) ([]T, error) {
+	tp := reflect.TypeOf((*T)(nil)).Elem()
+	kind, n := componentsOfType(tp)
+	if kind == reflect.Invalid || n != int(acr.Type.Components()) {
+		return nil, fmt.Errorf("gltf: cannot read accessor of type %v as %v", acr.Type, tp)
+	}
+	if uint32(cap(buffer)) < acr.Count {
+		buffer = make([]T, acr.Count)
+	} else {
+		buffer = buffer[:acr.Count]
+	}
+	if acr.Count == 0 {
+		return buffer, nil
+	}
+	var reuse interface{}
+	if reflect.TypeOf(binary.MakeSlice(acr.ComponentType, acr.Type, 0)) == reflect.TypeOf(buffer) {
+		reuse = buffer
+	}
+	data, err := ReadAccessor(doc, acr, reuse)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		clear(buffer)
+		return buffer, nil
+	}
+	if out, ok := data.([]T); ok && !acr.Normalized {
+		return out, nil
+	}
+
+	values := make([]float64, int(acr.Count)*n)
+	src := reflect.ValueOf(data).UnsafePointer()
+	switch acr.ComponentType {
+	case gltf.ComponentByte:
+		loadComponents(values, unsafe.Slice((*int8)(src), len(values)), acr.Normalized, math.MaxInt8)
+	case gltf.ComponentUbyte:
+		loadComponents(values, unsafe.Slice((*uint8)(src), len(values)), acr.Normalized, math.MaxUint8)
+	case gltf.ComponentShort:
+		loadComponents(values, unsafe.Slice((*int16)(src), len(values)), acr.Normalized, math.MaxInt16)
+	case gltf.ComponentUshort:
+		loadComponents(values, unsafe.Slice((*uint16)(src), len(values)), acr.Normalized, math.MaxUint16)
+	case gltf.ComponentUint:
+		loadComponents(values, unsafe.Slice((*uint32)(src), len(values)), acr.Normalized, math.MaxUint32)
+	case gltf.ComponentFloat:
+		loadComponents(values, unsafe.Slice((*float32)(src), len(values)), false, 1)
+	default:
+		return nil, errComponentType(acr.ComponentType)
+	}
+
+	dst := unsafe.Pointer(unsafe.SliceData(buffer))
+	switch kind {
+	case reflect.Int8:
+		storeComponents(unsafe.Slice((*int8)(dst), len(values)), values, acr.Normalized, math.MinInt8, math.MaxInt8)
+	case reflect.Uint8:
+		storeComponents(unsafe.Slice((*uint8)(dst), len(values)), values, acr.Normalized, 0, math.MaxUint8)
+	case reflect.Int16:
+		storeComponents(unsafe.Slice((*int16)(dst), len(values)), values, acr.Normalized, math.MinInt16, math.MaxInt16)
+	case reflect.Uint16:
+		storeComponents(unsafe.Slice((*uint16)(dst), len(values)), values, acr.Normalized, 0, math.MaxUint16)
+	case reflect.Int32:
+		storeComponents(unsafe.Slice((*int32)(dst), len(values)), values, acr.Normalized, math.MinInt32, math.MaxInt32)
+	case reflect.Uint32:
+		storeComponents(unsafe.Slice((*uint32)(dst), len(values)), values, acr.Normalized, 0, math.MaxUint32)
+	case reflect.Float32:
+		storeFloats(unsafe.Slice((*float32)(dst), len(values)), values)
+	case reflect.Float64:
+		storeFloats(unsafe.Slice((*float64)(dst), len(values)), values)
+	}
+	return buffer, nil
+}
+
+// componentsOfType returns the numeric kind of the components of tp and how many of them it holds.
+// It returns reflect.Invalid if tp is not supported by ReadAs.
+func componentsOfType(tp reflect.Type) (reflect.Kind, int) {
+	n := 1
+	for tp.Kind() == reflect.Array {
+		n *= tp.Len()
+		tp = tp.Elem()
+	}
+	switch k := tp.Kind(); k {
+	case reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16,
+		reflect.Int32, reflect.Uint32, reflect.Float32, reflect.Float64:
+		return k, n
+	}
+	return reflect.Invalid, 0
+}
+
+type number interface {
+	~int8 | ~uint8 | ~int16 | ~uint16 | ~int32 | ~uint32 | ~float32 | ~float64
+}
+
+// loadComponents converts src into dst, denormalizing the values into [-1, 1] when normalized is true.
+func loadComponents[S number](dst []float64, src []S, normalized bool, max float64) {
+	for i, v := range src {
+		if normalized {
+			dst[i] = math.Max(float64(v)/max, -1)
+		} else {
+			dst[i] = float64(v)
+		}
+	}
+}
+
+// storeComponents converts src into the integers dst,
+// mapping [-1, 1] to [-max, max] when normalized is true.
+func storeComponents[D number](dst []D, src []float64, normalized bool, min, max float64) {
+	for i, v := range src {
+		if normalized {
+			v *= max
+		}
+		dst[i] = D(math.Min(math.Max(math.Round(v), min), max))
+	}
+}
+
+func storeFloats[D number](dst []D, src []float64) {
+	for i, v := range src {
+		dst[i] = D(v)
+	}
+}
@@ -0,0 +1,89 @@
+package modeler
+
+import (
+	"testing"
+
+	"github.com/flywave/gltf"
+	"github.com/go-test/deep"
+)
+
+func TestReadAs(t *testing.T) {
+	doc := gltf.NewDocument()
+	floats := WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{1, -2, 0.5}, {300, 2.4, -0.5}})
+	normalized := WriteAccessor(doc, gltf.TargetArrayBuffer, [][2]uint8{{255, 0}, {51, 102}})
+	doc.Accessors[normalized].Normalized = true
+	signed := WriteAccessor(doc, gltf.TargetArrayBuffer, [][2]int16{{32767, -32768}, {0, 16384}})
+	doc.Accessors[signed].Normalized = true
+	ids := WriteAccessor(doc, gltf.TargetArrayBuffer, []uint16{1, 2, 65535})
+	matrices := WriteAccessor(doc, gltf.TargetNone, [][2][2]int8{{{1, 2}, {3, 4}}})
+	sparse := WriteAccessor(doc, gltf.TargetNone, []uint8{1, 2, 3})
+	doc.Accessors[sparse].Sparse = &gltf.Sparse{
+		Count:   1,
+		Indices: gltf.SparseIndices{BufferView: WriteBufferView(doc, gltf.TargetNone, []uint8{2}), ComponentType: gltf.ComponentUbyte},
+		Values:  gltf.SparseValues{BufferView: WriteBufferView(doc, gltf.TargetNone, []uint8{9})},
+	}
+	doc.Accessors = append(doc.Accessors, &gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorScalar, Count: 2})
+	zeros := uint32(len(doc.Accessors) - 1)
+
+	tests := []struct {
+		name    string
+		read    func() (interface{}, error)
+		want    interface{}
+		wantErr bool
+	}{
+		{"same type", func() (interface{}, error) { return ReadAs[[3]float32](doc, doc.Accessors[floats], nil) },
+			[][3]float32{{1, -2, 0.5}, {300, 2.4, -0.5}}, false},
+		{"float to int", func() (interface{}, error) { return ReadAs[[3]uint8](doc, doc.Accessors[floats], nil) },
+			[][3]uint8{{1, 0, 1}, {255, 2, 0}}, false},
+		{"float to float64", func() (interface{}, error) { return ReadAs[[3]float64](doc, doc.Accessors[floats], nil) },
+			[][3]float64{{1, -2, 0.5}, {300, float64(float32(2.4)), -0.5}}, false},
+		{"denormalize", func() (interface{}, error) { return ReadAs[[2]float32](doc, doc.Accessors[normalized], nil) },
+			[][2]float32{{1, 0}, {0.2, 0.4}}, false},
+		{"rescale", func() (interface{}, error) { return ReadAs[[2]uint16](doc, doc.Accessors[normalized], nil) },
+			[][2]uint16{{65535, 0}, {13107, 26214}}, false},
+		{"normalized same type", func() (interface{}, error) { return ReadAs[[2]uint8](doc, doc.Accessors[normalized], nil) },
+			[][2]uint8{{255, 0}, {51, 102}}, false},
+		{"signed", func() (interface{}, error) { return ReadAs[[2]float32](doc, doc.Accessors[signed], nil) },
+			[][2]float32{{1, -1}, {0, float32(16384.0 / 32767)}}, false},
+		{"signed to byte", func() (interface{}, error) { return ReadAs[[2]int8](doc, doc.Accessors[signed], nil) },
+			[][2]int8{{127, -127}, {0, 64}}, false},
+		{"scalar", func() (interface{}, error) { return ReadAs[uint32](doc, doc.Accessors[ids], nil) },
+			[]uint32{1, 2, 65535}, false},
+		{"matrix", func() (interface{}, error) { return ReadAs[[2][2]float32](doc, doc.Accessors[matrices], nil) },
+			[][2][2]float32{{{1, 2}, {3, 4}}}, false},
+		{"flat matrix", func() (interface{}, error) { return ReadAs[[4]int32](doc, doc.Accessors[matrices], nil) },
+			[][4]int32{{1, 2, 3, 4}}, false},
+		{"sparse", func() (interface{}, error) { return ReadAs[float32](doc, doc.Accessors[sparse], nil) },
+			[]float32{1, 2, 9}, false},
+		{"zeros", func() (interface{}, error) { return ReadAs[float32](doc, doc.Accessors[zeros], []float32{5, 5, 5}) },
+			[]float32{0, 0}, false},
+		{"components mismatch", func() (interface{}, error) { return ReadAs[[2]float32](doc, doc.Accessors[floats], nil) },
+			[][2]float32(nil), true},
+		{"unsupported type", func() (interface{}, error) { return ReadAs[string](doc, doc.Accessors[ids], nil) },
+			[]string(nil), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.read()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadAs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("ReadAs() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestReadAs_ReuseBuffer(t *testing.T) {
+	doc := gltf.NewDocument()
+	index := WriteAccessor(doc, gltf.TargetArrayBuffer, []uint8{1, 2})
+	buffer := make([]float32, 4)
+	got, err := ReadAs(doc, doc.Accessors[index], buffer)
+	if err != nil {
+		t.Fatalf("ReadAs() error = %v", err)
+	}
+	if &got[0] != &buffer[0] || len(got) != 2 {
+		t.Error("ReadAs() expected buffer to be reused")
+	}
+}
@@ -1,6 +1,7 @@
 package modeler
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 
@@ -100,6 +101,41 @@ func TestReadAccessor_Buffered(t *testing.T) {
 	}
 }
 
+type countingReaderAt struct {
+	r *bytes.Reader
+	n int
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	c.n += len(p)
+	return c.r.ReadAt(p, off)
+}
+
+func TestReadAccessor_Source(t *testing.T) {
+	src := &countingReaderAt{r: bytes.NewReader([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})}
+	doc := &gltf.Document{Buffers: []*gltf.Buffer{
+		{ByteLength: 16, Source: src},
+	}, BufferViews: []*gltf.BufferView{{
+		Buffer: 0, ByteLength: 12, ByteOffset: 4, ByteStride: 4,
+	}}}
+	acr := &gltf.Accessor{
+		BufferView: gltf.Index(0), ComponentType: gltf.ComponentUshort, Type: gltf.AccessorScalar, Count: 3,
+	}
+	got, err := ReadAccessor(doc, acr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint16{0x0605, 0x0a09, 0x0e0d}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAccessor() = %v, want %v", got, want)
+	}
+	if src.n != 10 {
+		t.Errorf("ReadAccessor() read %d bytes, want 10", src.n)
+	}
+	if doc.Buffers[0].Data != nil {
+		t.Error("ReadAccessor() loaded the whole buffer")
+	}
+}
+
 func TestReadAccessor(t *testing.T) {
 	type args struct {
 		doc *gltf.Document
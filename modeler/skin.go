@@ -0,0 +1,146 @@
+package modeler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/flywave/gltf"
+)
+
+// WriteSkin adds a new skin to doc whose joints are the given nodes
+// and writes the inverse bind matrices of the joints computed from their current world transforms,
+// so the current pose of the joints becomes the bind pose.
+// skeleton is the optional node used as root of the joints hierarchy.
+// If success it returns the index of the new skin.
+func WriteSkin(doc *gltf.Document, joints []uint32, skeleton *uint32) (uint32, error) {
+	if len(joints) == 0 {
+		return 0, errors.New("gltf: skin has no joints")
+	}
+	seen := make(map[uint32]bool, len(joints))
+	for _, j := range joints {
+		if int(j) >= len(doc.Nodes) {
+			return 0, fmt.Errorf("gltf: joint node %d does not exist", j)
+		}
+		if seen[j] {
+			return 0, fmt.Errorf("gltf: joint node %d is repeated", j)
+		}
+		seen[j] = true
+	}
+	if skeleton != nil && int(*skeleton) >= len(doc.Nodes) {
+		return 0, fmt.Errorf("gltf: skeleton node %d does not exist", *skeleton)
+	}
+	world, err := gltf.WorldMatrices(doc)
+	if err != nil {
+		return 0, err
+	}
+	matrices := make([][4][4]float32, len(joints))
+	for i, j := range joints {
+		inv, err := gltf.InvertMatrix(world[j])
+		if err != nil {
+			return 0, fmt.Errorf("gltf: joint node %d: %w", j, err)
+		}
+		for c := 0; c < 4; c++ {
+			copy(matrices[i][c][:], inv[c*4:c*4+4])
+		}
+	}
+	skin := &gltf.Skin{
+		InverseBindMatrices: gltf.Index(WriteAccessor(doc, gltf.TargetNone, matrices)),
+		Joints:              append([]uint32(nil), joints...),
+	}
+	if skeleton != nil {
+		skin.Skeleton = gltf.Index(*skeleton)
+	}
+	doc.Skins = append(doc.Skins, skin)
+	return uint32(len(doc.Skins) - 1), nil
+}
+
+// skinWeightsTolerance is the maximum difference to 1 of the sum of the weights of a vertex.
+const skinWeightsTolerance = 1e-3
+
+// CheckSkinWeights checks the JOINTS_n and WEIGHTS_n attributes of the primitives
+// of the mesh instantiated by node: every joint index must be lower than the number of joints
+// of the node skin and the weights of each vertex must add up to 1.
+//
+// If renormalize is true the weights that don't add up to 1 are scaled instead of returning an error.
+// In that case the weights of the primitive are written to new FLOAT WEIGHTS_n accessors.
+func CheckSkinWeights(doc *gltf.Document, node uint32, renormalize bool) error {
+	if int(node) >= len(doc.Nodes) {
+		return fmt.Errorf("gltf: node %d does not exist", node)
+	}
+	n := doc.Nodes[node]
+	if n.Skin == nil || int(*n.Skin) >= len(doc.Skins) || n.Mesh == nil || int(*n.Mesh) >= len(doc.Meshes) {
+		return fmt.Errorf("gltf: node %d has no skinned mesh", node)
+	}
+	joints := uint32(len(doc.Skins[*n.Skin].Joints))
+	for i, p := range doc.Meshes[*n.Mesh].Primitives {
+		if err := checkPrimitiveWeights(doc, p, joints, renormalize); err != nil {
+			return fmt.Errorf("gltf: mesh %d primitive %d: %w", *n.Mesh, i, err)
+		}
+	}
+	return nil
+}
+
+func checkPrimitiveWeights(doc *gltf.Document, p *gltf.Primitive, joints uint32, renormalize bool) error {
+	var weights [][][4]float32
+	for set := 0; ; set++ {
+		jointsIndex, okJoints := p.Attributes[fmt.Sprintf("JOINTS_%d", set)]
+		weightsIndex, okWeights := p.Attributes[fmt.Sprintf("WEIGHTS_%d", set)]
+		if !okJoints && !okWeights {
+			break
+		}
+		if okJoints != okWeights || int(jointsIndex) >= len(doc.Accessors) || int(weightsIndex) >= len(doc.Accessors) {
+			return fmt.Errorf("JOINTS_%d and WEIGHTS_%d must be defined together", set, set)
+		}
+		j, err := ReadAs[[4]uint32](doc, doc.Accessors[jointsIndex], nil)
+		if err != nil {
+			return err
+		}
+		w, err := ReadAs[[4]float32](doc, doc.Accessors[weightsIndex], nil)
+		if err != nil {
+			return err
+		}
+		if len(j) != len(w) || (len(weights) > 0 && len(w) != len(weights[0])) {
+			return fmt.Errorf("JOINTS_%d and WEIGHTS_%d have different counts", set, set)
+		}
+		for v, e := range j {
+			for _, joint := range e {
+				if joint >= joints {
+					return fmt.Errorf("vertex %d references joint %d but the skin has %d joints", v, joint, joints)
+				}
+			}
+		}
+		weights = append(weights, w)
+	}
+	if len(weights) == 0 {
+		return nil
+	}
+
+	var changed bool
+	for v := range weights[0] {
+		var sum float64
+		for _, w := range weights {
+			for _, x := range w[v] {
+				sum += float64(x)
+			}
+		}
+		if math.Abs(sum-1) <= skinWeightsTolerance {
+			continue
+		}
+		if !renormalize || sum == 0 {
+			return fmt.Errorf("the weights of vertex %d add up to %v", v, sum)
+		}
+		for _, w := range weights {
+			for k := range w[v] {
+				w[v][k] = float32(float64(w[v][k]) / sum)
+			}
+		}
+		changed = true
+	}
+	if changed {
+		for set, w := range weights {
+			p.Attributes[fmt.Sprintf("WEIGHTS_%d", set)] = WriteAccessor(doc, gltf.TargetArrayBuffer, w)
+		}
+	}
+	return nil
+}
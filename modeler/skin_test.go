@@ -0,0 +1,112 @@
+package modeler
+
+import (
+	"testing"
+
+	"github.com/flywave/gltf"
+	"github.com/go-test/deep"
+)
+
+func TestWriteSkin(t *testing.T) {
+	doc := gltf.NewDocument()
+	doc.Nodes = []*gltf.Node{
+		{Translation: [3]float32{1, 0, 0}, Children: []uint32{1}},
+		{Translation: [3]float32{0, 2, 0}, Scale: [3]float32{2, 2, 2}},
+	}
+	index, err := WriteSkin(doc, []uint32{0, 1}, gltf.Index(0))
+	if err != nil {
+		t.Fatalf("WriteSkin() error = %v", err)
+	}
+	skin := doc.Skins[index]
+	if diff := deep.Equal(skin.Joints, []uint32{0, 1}); diff != nil || *skin.Skeleton != 0 {
+		t.Errorf("WriteSkin() = %+v", skin)
+	}
+	acr := doc.Accessors[*skin.InverseBindMatrices]
+	if acr.Type != gltf.AccessorMat4 || acr.ComponentType != gltf.ComponentFloat || acr.Count != 2 {
+		t.Errorf("WriteSkin() accessor = %+v", acr)
+	}
+	data, err := ReadAccessor(doc, acr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][4][4]float32{
+		{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {-1, 0, 0, 1}},
+		{{0.5, 0, 0, 0}, {0, 0.5, 0, 0}, {0, 0, 0.5, 0}, {-0.5, -1, 0, 1}},
+	}
+	if diff := deep.Equal(data, want); diff != nil {
+		t.Errorf("WriteSkin() inverse bind matrices = %v", diff)
+	}
+
+	doc.Nodes = append(doc.Nodes, &gltf.Node{Matrix: [16]float32{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}})
+	for _, joints := range [][]uint32{nil, {0, 0}, {5}, {2}} {
+		if _, err := WriteSkin(doc, joints, nil); err == nil {
+			t.Errorf("WriteSkin(%v) expected error", joints)
+		}
+	}
+	if _, err := WriteSkin(doc, []uint32{0}, gltf.Index(5)); err == nil {
+		t.Error("WriteSkin() expected error for a missing skeleton")
+	}
+}
+
+func TestCheckSkinWeights(t *testing.T) {
+	joints := [][4]uint8{{0, 1, 0, 0}, {1, 0, 0, 0}}
+	weights := [][4]float32{{0.5, 0.5, 0, 0}, {1, 0, 0, 0}}
+	tests := []struct {
+		name        string
+		joints      interface{}
+		weights     interface{}
+		node        uint32
+		modify      func(p *gltf.Primitive)
+		renormalize bool
+		want        [][4]float32
+		wantErr     bool
+	}{
+		{"valid", joints, weights, 2, nil, false, nil, false},
+		{"normalized", [][4]uint16{{0, 1, 0, 0}, {1, 0, 0, 0}}, [][4]uint8{{128, 127, 0, 0}, {255, 0, 0, 0}}, 2, nil, false, nil, false},
+		{"joint out of range", [][4]uint8{{0, 2, 0, 0}, {1, 0, 0, 0}}, weights, 2, nil, false, nil, true},
+		{"not normalized", joints, [][4]float32{{0.5, 0.3, 0, 0}, {1, 0, 0, 0}}, 2, nil, false, nil, true},
+		{"renormalize", joints, [][4]float32{{2, 2, 0, 0}, {1, 0, 0, 0}}, 2, nil, true,
+			[][4]float32{{0.5, 0.5, 0, 0}, {1, 0, 0, 0}}, false},
+		{"zero weights", joints, [][4]float32{{0, 0, 0, 0}, {1, 0, 0, 0}}, 2, nil, true, nil, true},
+		{"count mismatch", [][4]uint8{{0, 1, 0, 0}}, weights, 2, nil, false, nil, true},
+		{"no skin", joints, weights, 0, nil, false, nil, true},
+		{"missing node", joints, weights, 5, nil, false, nil, true},
+		{"joints without weights", joints, weights, 2, func(p *gltf.Primitive) { p.Attributes["JOINTS_1"] = 0 }, false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gltf.NewDocument()
+			position := WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}})
+			weightsIndex := WriteAccessor(doc, gltf.TargetArrayBuffer, tt.weights)
+			doc.Accessors[weightsIndex].Normalized = doc.Accessors[weightsIndex].ComponentType != gltf.ComponentFloat
+			doc.Meshes = []*gltf.Mesh{{Primitives: []*gltf.Primitive{{Attributes: gltf.Attribute{
+				gltf.POSITION:  position,
+				gltf.JOINTS_0:  WriteAccessor(doc, gltf.TargetArrayBuffer, tt.joints),
+				gltf.WEIGHTS_0: weightsIndex,
+			}}}}}
+			doc.Skins = []*gltf.Skin{{Joints: []uint32{0, 1}}}
+			doc.Nodes = []*gltf.Node{{}, {}, {Mesh: gltf.Index(0), Skin: gltf.Index(0)}}
+			p := doc.Meshes[0].Primitives[0]
+			if tt.modify != nil {
+				tt.modify(p)
+			}
+			before := p.Attributes[gltf.WEIGHTS_0]
+			if err := CheckSkinWeights(doc, tt.node, tt.renormalize); (err != nil) != tt.wantErr {
+				t.Fatalf("CheckSkinWeights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				if p.Attributes[gltf.WEIGHTS_0] != before {
+					t.Error("CheckSkinWeights() expected weights to be left unchanged")
+				}
+				return
+			}
+			got, err := ReadAccessor(doc, doc.Accessors[p.Attributes[gltf.WEIGHTS_0]], nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("CheckSkinWeights() weights = %v", diff)
+			}
+		})
+	}
+}
@@ -217,6 +217,125 @@ func WriteAccessorsInterleaved(doc *gltf.Document, data ...interface{}) ([]uint3
 	return indices, nil
 }
 
+// WriteSparseAccessor adds a new sparse accessor to doc with count elements
+// initialized with the data of the accessor base, or with zeros if base is nil,
+// where the elements at indices are replaced by values.
+// indices must be strictly increasing and lower than count
+// and values must have one element per index with the same type as base.
+// The sparse indices are stored with the smallest component type that fits the largest index.
+// Returns the index of the new accessor.
+func WriteSparseAccessor(doc *gltf.Document, count uint32, base *uint32, indices []uint32, values interface{}) (uint32, error) {
+	c, a, l := binary.Type(values)
+	if l == 0 || int(l) != len(indices) {
+		return 0, fmt.Errorf("gltf: sparse accessor has %d indices and %d values", len(indices), l)
+	}
+	for i, index := range indices {
+		if index >= count || (i > 0 && index <= indices[i-1]) {
+			return 0, errors.New("gltf: sparse indices must be strictly increasing and lower than count")
+		}
+	}
+	acr, err := sparseBase(doc, count, base, c, a)
+	if err != nil {
+		return 0, err
+	}
+	var data interface{}
+	acr.Sparse = &gltf.Sparse{Count: l}
+	switch max := indices[len(indices)-1]; {
+	case max <= math.MaxUint8:
+		acr.Sparse.Indices.ComponentType = gltf.ComponentUbyte
+		data = convertIndices[uint8](indices)
+	case max <= math.MaxUint16:
+		acr.Sparse.Indices.ComponentType = gltf.ComponentUshort
+		data = convertIndices[uint16](indices)
+	default:
+		acr.Sparse.Indices.ComponentType = gltf.ComponentUint
+		data = indices
+	}
+	ensurePadding(doc)
+	acr.Sparse.Indices.BufferView = WriteBufferView(doc, gltf.TargetNone, data)
+	ensurePadding(doc)
+	acr.Sparse.Values.BufferView = WriteBufferView(doc, gltf.TargetNone, values)
+	doc.Accessors = append(doc.Accessors, acr)
+	return uint32(len(doc.Accessors) - 1), nil
+}
+
+// WriteSparseAccessorDiff adds a new accessor to doc holding data
+// where only the elements that differ from the accessor base, or from zero if base is nil,
+// are stored as sparse values.
+// If no element differs the new accessor has no sparse storage.
+// Returns the index of the new accessor.
+func WriteSparseAccessorDiff(doc *gltf.Document, base *uint32, data interface{}) (uint32, error) {
+	c, a, l := binary.Type(data)
+	acr, err := sparseBase(doc, l, base, c, a)
+	if err != nil {
+		return 0, err
+	}
+	v := reflect.ValueOf(data)
+	var ref reflect.Value
+	if base != nil {
+		baseData, err := ReadAccessor(doc, doc.Accessors[*base], nil)
+		if err != nil {
+			return 0, err
+		}
+		if baseData != nil {
+			ref = reflect.ValueOf(baseData)
+		}
+	}
+	zero := reflect.Zero(v.Type().Elem())
+	var indices []uint32
+	for i := 0; i < v.Len(); i++ {
+		r := zero
+		if ref.IsValid() {
+			r = ref.Index(i)
+		}
+		if !v.Index(i).Equal(r) {
+			indices = append(indices, uint32(i))
+		}
+	}
+	if len(indices) == 0 {
+		doc.Accessors = append(doc.Accessors, acr)
+		return uint32(len(doc.Accessors) - 1), nil
+	}
+	values := reflect.MakeSlice(v.Type(), len(indices), len(indices))
+	for i, index := range indices {
+		values.Index(i).Set(v.Index(int(index)))
+	}
+	return WriteSparseAccessor(doc, l, base, indices, values.Interface())
+}
+
+// sparseBase returns an accessor that shares the storage of base
+// after checking that it is compatible with the given layout.
+func sparseBase(doc *gltf.Document, count uint32, base *uint32, c gltf.ComponentType, a gltf.AccessorType) (*gltf.Accessor, error) {
+	acr := &gltf.Accessor{ComponentType: c, Type: a, Count: count}
+	if base == nil {
+		return acr, nil
+	}
+	if int(*base) >= len(doc.Accessors) {
+		return nil, fmt.Errorf("gltf: base accessor %d does not exist", *base)
+	}
+	b := doc.Accessors[*base]
+	if b.ComponentType != c || b.Type != a || b.Count != count {
+		return nil, fmt.Errorf("gltf: base accessor %d does not match the sparse values layout", *base)
+	}
+	if b.Sparse != nil {
+		return nil, fmt.Errorf("gltf: base accessor %d is already sparse", *base)
+	}
+	if b.BufferView != nil {
+		acr.BufferView = gltf.Index(*b.BufferView)
+	}
+	acr.ByteOffset = b.ByteOffset
+	acr.Normalized = b.Normalized
+	return acr, nil
+}
+
+func convertIndices[T uint8 | uint16](indices []uint32) []T {
+	out := make([]T, len(indices))
+	for i, index := range indices {
+		out[i] = T(index)
+	}
+	return out
+}
+
 // CustomAttribute defines an application-specific attribute
 type CustomAttribute struct {
 	Name string
@@ -381,7 +500,13 @@ func lastBuffer(doc *gltf.Document) *gltf.Buffer {
 	if len(doc.Buffers) == 0 {
 		doc.Buffers = append(doc.Buffers, new(gltf.Buffer))
 	}
-	return doc.Buffers[len(doc.Buffers)-1]
+	buf := doc.Buffers[len(doc.Buffers)-1]
+	if err := buf.Load(); err != nil {
+		// Never append to a buffer whose existing content can't be read.
+		buf = new(gltf.Buffer)
+		doc.Buffers = append(doc.Buffers, buf)
+	}
+	return buf
 }
 
 func getPadding(offset uint32) uint32 {
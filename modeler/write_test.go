@@ -740,3 +740,91 @@ type errReader struct{}
 func (r *errReader) Read(p []byte) (int, error) {
 	return 0, errors.New("")
 }
+
+func TestWriteSparseAccessor(t *testing.T) {
+	doc := gltf.NewDocument()
+	base := WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{1, 1, 1}, {2, 2, 2}, {3, 3, 3}})
+	tests := []struct {
+		name      string
+		count     uint32
+		base      *uint32
+		indices   []uint32
+		values    interface{}
+		want      interface{}
+		indexType gltf.ComponentType
+		wantErr   bool
+	}{
+		{"base", 3, gltf.Index(base), []uint32{1}, [][3]float32{{5, 5, 5}},
+			[][3]float32{{1, 1, 1}, {5, 5, 5}, {3, 3, 3}}, gltf.ComponentUbyte, false},
+		{"zeros", 3, nil, []uint32{0, 2}, []uint16{4, 5}, []uint16{4, 0, 5}, gltf.ComponentUbyte, false},
+		{"ushort indices", 300, nil, []uint32{299}, []uint8{1}, nil, gltf.ComponentUshort, false},
+		{"uint indices", 70000, nil, []uint32{69999}, []uint8{1}, nil, gltf.ComponentUint, false},
+		{"length mismatch", 3, nil, []uint32{0, 1}, []uint8{1}, nil, 0, true},
+		{"unsorted", 3, nil, []uint32{1, 0}, []uint8{1, 2}, nil, 0, true},
+		{"out of range", 3, nil, []uint32{3}, []uint8{1}, nil, 0, true},
+		{"type mismatch", 3, gltf.Index(base), []uint32{0}, []float32{1}, nil, 0, true},
+		{"missing base", 3, gltf.Index(100), []uint32{0}, [][3]float32{{1, 1, 1}}, nil, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := WriteSparseAccessor(doc, tt.count, tt.base, tt.indices, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteSparseAccessor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			acr := doc.Accessors[index]
+			if acr.Sparse.Indices.ComponentType != tt.indexType {
+				t.Errorf("WriteSparseAccessor() index type = %v, want %v", acr.Sparse.Indices.ComponentType, tt.indexType)
+			}
+			if tt.want == nil {
+				return
+			}
+			got, err := ReadAccessor(doc, acr, nil)
+			if err != nil {
+				t.Fatalf("ReadAccessor() error = %v", err)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("WriteSparseAccessor() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestWriteSparseAccessorDiff(t *testing.T) {
+	doc := gltf.NewDocument()
+	base := WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{1, 1, 1}, {2, 2, 2}, {3, 3, 3}})
+	data := [][3]float32{{1, 1, 1}, {2, 2, 2}, {0, 3, 3}}
+	index, err := WriteSparseAccessorDiff(doc, gltf.Index(base), data)
+	if err != nil {
+		t.Fatalf("WriteSparseAccessorDiff() error = %v", err)
+	}
+	acr := doc.Accessors[index]
+	if acr.Sparse == nil || acr.Sparse.Count != 1 || *acr.BufferView != *doc.Accessors[base].BufferView {
+		t.Errorf("WriteSparseAccessorDiff() = %+v", acr)
+	}
+	if got, _ := ReadAccessor(doc, acr, nil); deep.Equal(got, data) != nil {
+		t.Errorf("WriteSparseAccessorDiff() data = %v, want %v", got, data)
+	}
+
+	index, err = WriteSparseAccessorDiff(doc, nil, []float32{0, 0, 7, 0})
+	if err != nil {
+		t.Fatalf("WriteSparseAccessorDiff() error = %v", err)
+	}
+	if got, _ := ReadAccessor(doc, doc.Accessors[index], nil); deep.Equal(got, []float32{0, 0, 7, 0}) != nil {
+		t.Errorf("WriteSparseAccessorDiff() data = %v", got)
+	}
+
+	index, err = WriteSparseAccessorDiff(doc, nil, []float32{0, 0})
+	if err != nil {
+		t.Fatalf("WriteSparseAccessorDiff() error = %v", err)
+	}
+	if acr := doc.Accessors[index]; acr.Sparse != nil || acr.BufferView != nil || acr.Count != 2 {
+		t.Errorf("WriteSparseAccessorDiff() = %+v, want empty accessor", acr)
+	}
+
+	if _, err = WriteSparseAccessorDiff(doc, gltf.Index(base), [][3]float32{{1, 1, 1}}); err == nil {
+		t.Error("WriteSparseAccessorDiff() expected count mismatch error")
+	}
+}
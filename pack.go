@@ -0,0 +1,318 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Pack moves the content of every buffer and of every image referenced
+// by an URI into a single buffer without URI, which is the layout
+// expected by the GLB BIN chunk.
+//
+// Images are rewritten to reference a new buffer view and get a mime type.
+// External resources whose content has not been loaded are read from fsys,
+// as a Decoder with that Fsys does. Pack fails without modifying doc
+// when the content of a resource can't be read, or when an extension
+// references buffers, as those references can't be updated.
+func Pack(doc *Document, fsys fs.FS) error {
+	if err := checkRemappable(doc); err != nil {
+		return err
+	}
+	if extensionReferencesBuffers(doc) {
+		return errors.New("gltf: an extension references buffer byte ranges that can't be packed")
+	}
+	d := &Decoder{Fsys: fsys}
+	buffers := make([][]byte, len(doc.Buffers))
+	for i, b := range doc.Buffers {
+		if err := b.Load(); err != nil {
+			return err
+		}
+		data := b.Data
+		if uint32(len(data)) < b.ByteLength && b.URI != "" {
+			var err error
+			if b.IsEmbeddedResource() {
+				data, err = b.MarshalData()
+			} else if err = validateURI("buffer", b.URI); err == nil {
+				data, err = d.readResource(b.URI, int64(b.ByteLength))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if uint32(len(data)) < b.ByteLength {
+			return fmt.Errorf("gltf: buffer %d content is not loaded", i)
+		}
+		buffers[i] = data[:b.ByteLength]
+	}
+	for i, bv := range doc.BufferViews {
+		if int(bv.Buffer) >= len(doc.Buffers) {
+			return fmt.Errorf("gltf: buffer view %d references a missing buffer", i)
+		}
+	}
+	images := make([][]byte, len(doc.Images))
+	for i, im := range doc.Images {
+		if im.BufferView != nil || im.URI == "" {
+			continue
+		}
+		var err error
+		images[i] = im.Data
+		if im.Data == nil {
+			if im.IsEmbeddedResource() {
+				images[i], err = im.MarshalData()
+			} else if err = validateURI("image", im.URI); err == nil {
+				images[i], err = d.readResource(im.URI, -1)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if images[i] == nil {
+			return fmt.Errorf("gltf: image %d content is not loaded", i)
+		}
+	}
+
+	var data []byte
+	offsets := make([]uint32, len(buffers))
+	for i, b := range buffers {
+		data = padBytes(data)
+		offsets[i] = uint32(len(data))
+		data = append(data, b...)
+	}
+	for _, bv := range doc.BufferViews {
+		bv.ByteOffset += offsets[bv.Buffer]
+		bv.Buffer = 0
+	}
+	for i, im := range doc.Images {
+		if images[i] == nil {
+			continue
+		}
+		if im.MimeType == "" {
+			im.MimeType = imageMimeType(im, images[i])
+		}
+		data = padBytes(data)
+		doc.BufferViews = append(doc.BufferViews, &BufferView{
+			ByteOffset: uint32(len(data)),
+			ByteLength: uint32(len(images[i])),
+		})
+		data = append(data, images[i]...)
+		im.BufferView = Index(uint32(len(doc.BufferViews) - 1))
+		im.URI = ""
+		im.Data = nil
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	bin := new(Buffer)
+	if len(doc.Buffers) > 0 && doc.Buffers[0].URI == "" {
+		bin = doc.Buffers[0]
+	}
+	bin.URI = ""
+	bin.ByteLength = uint32(len(data))
+	bin.Data = data
+	bin.Source = nil
+	doc.Buffers = []*Buffer{bin}
+	return nil
+}
+
+// Unpack is the inverse of Pack. It moves the images stored in buffer views
+// to external files and gives an URI to every buffer that doesn't have one,
+// writing their content to fsys.
+// The resulting document can be encoded with AsBinary set to false.
+//
+// Buffers are named buffer<index>.bin and images image<index>.<ext>.
+// The buffer views that only stored the moved images are removed
+// when that doesn't change the index of any other buffer view,
+// which is always the case for documents created by Pack.
+func Unpack(doc *Document, fsys CreateFS) error {
+	if fsys == nil {
+		return errors.New("gltf: no file system to write the unpacked resources")
+	}
+	for i, b := range doc.Buffers {
+		if err := b.Load(); err != nil {
+			return err
+		}
+		if uint32(len(b.Data)) < b.ByteLength {
+			return fmt.Errorf("gltf: buffer %d content is not loaded", i)
+		}
+	}
+	images := make([][]byte, len(doc.Images))
+	for i, im := range doc.Images {
+		if im.BufferView == nil {
+			continue
+		}
+		if int(*im.BufferView) >= len(doc.BufferViews) {
+			return fmt.Errorf("gltf: image %d references a missing buffer view", i)
+		}
+		bv := doc.BufferViews[*im.BufferView]
+		if int(bv.Buffer) >= len(doc.Buffers) {
+			return fmt.Errorf("gltf: buffer view %d references a missing buffer", *im.BufferView)
+		}
+		data, err := doc.Buffers[bv.Buffer].ReadRange(bv.ByteOffset, bv.ByteLength)
+		if err != nil {
+			return err
+		}
+		images[i] = append([]byte(nil), data...)
+	}
+
+	e := &Encoder{Fsys: fsys}
+	uris := make([]string, len(doc.Images))
+	moved := make([]bool, len(doc.Images))
+	for i, im := range doc.Images {
+		if images[i] == nil {
+			continue
+		}
+		uris[i] = fmt.Sprintf("image%d%s", i, imageExtension(im.MimeType, images[i]))
+		if err := e.encodeResource("image", uris[i], images[i]); err != nil {
+			return err
+		}
+		moved[i] = true
+	}
+	trimImageBufferViews(doc, moved)
+	for i, im := range doc.Images {
+		if moved[i] {
+			im.URI = uris[i]
+			im.BufferView = nil
+			im.Data = images[i]
+		}
+	}
+	for i, b := range doc.Buffers {
+		if b.URI != "" {
+			continue
+		}
+		b.URI = fmt.Sprintf("buffer%d.bin", i)
+		if err := e.encodeResource("buffer", b.URI, b.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trimImageBufferViews removes the trailing buffer views that only stored
+// the images that have been moved out of the buffers, truncating the buffers
+// when those images were stored at their end. Buffer views in other positions
+// are kept, as removing them would shift the indices used by extensions.
+func trimImageBufferViews(doc *Document, moved []bool) {
+	removable := make([]bool, len(doc.BufferViews))
+	for i, im := range doc.Images {
+		if moved[i] && int(*im.BufferView) < len(removable) {
+			removable[*im.BufferView] = true
+		}
+	}
+	for _, acr := range doc.Accessors {
+		if acr.BufferView != nil && int(*acr.BufferView) < len(removable) {
+			removable[*acr.BufferView] = false
+		}
+		if acr.Sparse != nil {
+			for _, i := range []uint32{acr.Sparse.Indices.BufferView, acr.Sparse.Values.BufferView} {
+				if int(i) < len(removable) {
+					removable[i] = false
+				}
+			}
+		}
+	}
+	for i, im := range doc.Images {
+		if !moved[i] && im.BufferView != nil && int(*im.BufferView) < len(removable) {
+			removable[*im.BufferView] = false
+		}
+	}
+	n := len(doc.BufferViews)
+	for n > 0 && removable[n-1] {
+		n--
+	}
+	trailing := doc.BufferViews[n:]
+	kept := doc.BufferViews[:n]
+	for bi, b := range doc.Buffers {
+		var ranges [][2]uint32
+		for _, bv := range trailing {
+			if int(bv.Buffer) == bi {
+				ranges = append(ranges, [2]uint32{bv.ByteOffset, bv.ByteOffset + bv.ByteLength})
+			}
+		}
+		if len(ranges) == 0 {
+			continue
+		}
+		sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+		start, end := ranges[0][0], ranges[0][1]
+		for _, r := range ranges[1:] {
+			if r[0] > end+3 {
+				start, end = 0, 0
+				break
+			}
+			end = max(end, r[1])
+		}
+		if start == 0 || end+3 < b.ByteLength {
+			continue
+		}
+		for _, bv := range kept {
+			if int(bv.Buffer) == bi && bv.ByteOffset+bv.ByteLength > start {
+				start = 0
+				break
+			}
+		}
+		if start == 0 {
+			continue
+		}
+		b.ByteLength = start
+		if uint32(len(b.Data)) > start {
+			b.Data = b.Data[:start]
+		}
+	}
+	for i := n; i < len(doc.BufferViews); i++ {
+		doc.BufferViews[i] = nil
+	}
+	doc.BufferViews = kept
+}
+
+func padBytes(data []byte) []byte {
+	if padding := len(data) % 4; padding != 0 {
+		data = append(data, make([]byte, 4-padding)...)
+	}
+	return data
+}
+
+// imageMimeType guesses the mime type of an image from its content,
+// the media type of its data uri or the extension of its uri.
+func imageMimeType(im *Image, data []byte) string {
+	if t := DetectImageMimeType(data); t != "" {
+		return t
+	}
+	if im.IsEmbeddedResource() {
+		if t := im.EmbeddedMimeType(); strings.HasPrefix(t, "image/") {
+			return t
+		}
+	} else if t, _, _ := strings.Cut(mime.TypeByExtension(path.Ext(im.URI)), ";"); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+func imageExtension(mimeType string, data []byte) string {
+	if mimeType == "" {
+		mimeType = DetectImageMimeType(data)
+	}
+	switch mimeType {
+	case MimeTypePNG:
+		return ".png"
+	case MimeTypeJPEG:
+		return ".jpg"
+	case MimeTypeWebP:
+		return ".webp"
+	case MimeTypeKTX2:
+		return ".ktx2"
+	case MimeTypeAVIF:
+		return ".avif"
+	case MimeTypeGIF:
+		return ".gif"
+	}
+	if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
@@ -0,0 +1,168 @@
+package gltf
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-test/deep"
+)
+
+var pngHeader = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 0}
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(doc *Document)
+		wantErr bool
+		check   func(t *testing.T, doc *Document)
+	}{
+		{"pack", nil, false, func(t *testing.T, doc *Document) {
+			want := &Document{
+				Asset: Asset{Version: "2.0"},
+				Buffers: []*Buffer{{ByteLength: 32, Data: []byte{
+					1, 2, 3, 4, 5, 6, 0, 0, 7, 8, 9, 10,
+					0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 0,
+					0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n',
+				}}},
+				BufferViews: []*BufferView{
+					{Buffer: 0, ByteOffset: 2, ByteLength: 4},
+					{Buffer: 0, ByteOffset: 8, ByteLength: 4},
+					{Buffer: 0, ByteOffset: 12, ByteLength: 12},
+					{Buffer: 0, ByteOffset: 24, ByteLength: 8},
+				},
+				Accessors: doc.Accessors,
+				Images: []*Image{
+					{BufferView: Index(2), MimeType: "image/png"},
+					{BufferView: Index(3), MimeType: "image/png"},
+				},
+			}
+			if diff := deep.Equal(doc, want); diff != nil {
+				t.Errorf("Pack() = %v", diff)
+			}
+
+			var glb bytes.Buffer
+			if err := NewEncoder(&glb).Encode(doc); err != nil {
+				t.Fatalf("Encoder.Encode() error = %v", err)
+			}
+			got := new(Document)
+			if err := NewDecoder(&glb).Decode(got); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			if !bytes.Equal(got.Buffers[0].Data, want.Buffers[0].Data) {
+				t.Errorf("Pack() BIN chunk = %v, want %v", got.Buffers[0].Data, want.Buffers[0].Data)
+			}
+		}},
+		{"fsys", func(doc *Document) {
+			doc.Buffers[0].Data = nil
+			doc.Images[0].Data = nil
+		}, false, func(t *testing.T, doc *Document) {
+			want := []byte{
+				1, 2, 3, 4, 5, 6, 0, 0, 7, 8, 9, 10,
+				0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 0,
+				0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n',
+			}
+			if !bytes.Equal(doc.Buffers[0].Data, want) {
+				t.Errorf("Pack() data = %v, want %v", doc.Buffers[0].Data, want)
+			}
+		}},
+		{"notLoaded", func(doc *Document) { doc.Images[1].Data = nil }, true, nil},
+		{"bufferExtension", func(doc *Document) {
+			doc.BufferViews[1].Extensions = Extensions{"EXT_meshopt_compression": testBufferRef{1}}
+		}, true, nil},
+		{"unpackNoFS", nil, false, func(t *testing.T, doc *Document) {
+			if err := Unpack(doc, nil); err == nil {
+				t.Error("Unpack() expected an error without file system")
+			}
+			if len(doc.BufferViews) != 4 || doc.Images[0].BufferView == nil {
+				t.Error("Unpack() modified the document")
+			}
+		}},
+		{"unpack", nil, false, func(t *testing.T, doc *Document) {
+			fsys := mockChunkReadHandler{fstest.MapFS{}}
+			if err := Unpack(doc, fsys); err != nil {
+				t.Fatalf("Unpack() error = %v", err)
+			}
+			if len(doc.BufferViews) != 2 {
+				t.Errorf("Unpack() left %d buffer views, want 2", len(doc.BufferViews))
+			}
+			wantBin := []byte{1, 2, 3, 4, 5, 6, 0, 0, 7, 8, 9, 10}
+			if b := doc.Buffers[0]; b.URI != "buffer0.bin" || b.ByteLength != 12 || !bytes.Equal(b.Data, wantBin) {
+				t.Errorf("Unpack() buffer = %+v", b)
+			}
+			files := map[string][]byte{
+				"buffer0.bin": wantBin,
+				"image0.png":  pngHeader,
+				"image1.png":  pngHeader[:8],
+			}
+			for name, want := range files {
+				if f, ok := fsys.MapFS[name]; !ok || !bytes.Equal(f.Data, want) {
+					t.Errorf("Unpack() file %s = %v, want %v", name, f, want)
+				}
+			}
+			for i, im := range doc.Images {
+				if im.BufferView != nil || im.URI == "" {
+					t.Errorf("Unpack() image %d = %+v", i, im)
+				}
+			}
+
+			var gltf bytes.Buffer
+			e := NewEncoderFS(&gltf, fsys)
+			e.AsBinary = false
+			if err := e.Encode(doc); err != nil {
+				t.Fatalf("Encoder.Encode() error = %v", err)
+			}
+			got := new(Document)
+			d := NewDecoderFS(&gltf, fsys)
+			d.LoadImages = true
+			if err := d.Decode(got); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			if diff := deep.Equal(got, doc); diff != nil {
+				t.Errorf("Unpack() round trip = %v", diff)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				Asset: Asset{Version: "2.0"},
+				Buffers: []*Buffer{
+					{ByteLength: 6, URI: "a.bin", Data: []byte{1, 2, 3, 4, 5, 6}},
+					{ByteLength: 4, URI: "data:application/octet-stream;base64,BwgJCg==", Data: []byte{7, 8, 9, 10}},
+				},
+				BufferViews: []*BufferView{
+					{Buffer: 0, ByteOffset: 2, ByteLength: 4},
+					{Buffer: 1, ByteLength: 4},
+				},
+				Accessors: []*Accessor{
+					{BufferView: Index(0), ComponentType: ComponentUshort, Type: AccessorScalar, Count: 2},
+					{BufferView: Index(1), ComponentType: ComponentUbyte, Type: AccessorScalar, Count: 4},
+				},
+				Images: []*Image{
+					{URI: "a.png", Data: pngHeader},
+					{URI: "b", Data: pngHeader[:8]},
+				},
+			}
+			if tt.modify != nil {
+				tt.modify(doc)
+			}
+			before := doc.Clone()
+			fsys := fstest.MapFS{
+				"a.bin": {Data: []byte{1, 2, 3, 4, 5, 6}},
+				"a.png": {Data: pngHeader},
+			}
+			if err := Pack(doc, fsys); (err != nil) != tt.wantErr {
+				t.Fatalf("Pack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if diff := deep.Equal(doc, before); diff != nil {
+					t.Errorf("Pack() modified the document on error: %v", diff)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, doc)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package gltf
+
+import "sort"
+
+// PruneReport describes the objects removed by Prune.
+type PruneReport struct {
+	// Removed lists the original indices of the removed objects, keyed by document property.
+	Removed map[string][]uint32
+	// BufferBytes is the number of bytes removed from the buffers.
+	BufferBytes int
+}
+
+type objectRef struct {
+	property string
+	index    uint32
+}
+
+// Prune removes the nodes that are not reachable from any scene and the
+// accessors, animations, buffer views, buffers, cameras, images, materials,
+// meshes, samplers, skins and textures that are no longer referenced,
+// then rewrites the indices of the remaining objects, including the ones held
+// by the extension payloads that implement IndexRemapper.
+// Objects referenced by such payloads are considered as used.
+// When a document has no scene all its nodes are kept.
+//
+// Animation channels targeting removed nodes are removed, and so are the
+// animations left without channels. Loaded buffers are compacted so that
+// only the byte ranges of the remaining buffer views are kept, preserving
+// their alignment; buffers referenced by extensions are not compacted.
+//
+// Buffers with a Source are loaded first, and if it fails the error is returned
+// without modifying doc. An error is also returned if an extension payload
+// was not decoded, as the objects it references are unknown.
+func Prune(doc *Document) (*PruneReport, error) {
+	if err := checkRemappable(doc); err != nil {
+		return nil, err
+	}
+	for _, b := range doc.Buffers {
+		if b != nil {
+			if err := b.Load(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	used := map[string][]bool{
+		PropertyAccessors:   make([]bool, len(doc.Accessors)),
+		PropertyAnimations:  make([]bool, len(doc.Animations)),
+		PropertyBuffers:     make([]bool, len(doc.Buffers)),
+		PropertyBufferViews: make([]bool, len(doc.BufferViews)),
+		PropertyCameras:     make([]bool, len(doc.Cameras)),
+		PropertyImages:      make([]bool, len(doc.Images)),
+		PropertyMaterials:   make([]bool, len(doc.Materials)),
+		PropertyMeshes:      make([]bool, len(doc.Meshes)),
+		PropertyNodes:       make([]bool, len(doc.Nodes)),
+		PropertySamplers:    make([]bool, len(doc.Samplers)),
+		PropertyScenes:      make([]bool, len(doc.Scenes)),
+		PropertySkins:       make([]bool, len(doc.Skins)),
+		PropertyTextures:    make([]bool, len(doc.Textures)),
+	}
+	var queue []objectRef
+	extBuffers := make([]bool, len(doc.Buffers))
+	w := &indexRewriter{dryRun: true}
+	w.remap = func(property string, index uint32) uint32 {
+		u := used[property]
+		if int(index) < len(u) && !u[index] {
+			u[index] = true
+			queue = append(queue, objectRef{property, index})
+		}
+		if w.inExtension && property == PropertyBuffers && int(index) < len(extBuffers) {
+			extBuffers[index] = true
+		}
+		return index
+	}
+	for i := range doc.Scenes {
+		w.remap(PropertyScenes, uint32(i))
+	}
+	if len(doc.Scenes) == 0 {
+		for i := range doc.Nodes {
+			w.remap(PropertyNodes, uint32(i))
+		}
+	}
+	w.extensions(doc.Extensions)
+	markUsedObjects(doc, w, &queue)
+
+	for i, anim := range doc.Animations {
+		if anim == nil {
+			continue
+		}
+		var channels []*Channel
+		for _, c := range anim.Channels {
+			if c.Target.Node == nil || (int(*c.Target.Node) < len(doc.Nodes) && used[PropertyNodes][*c.Target.Node]) {
+				channels = append(channels, c)
+			}
+		}
+		if len(channels) > 0 {
+			used[PropertyAnimations][i] = true
+			anim.Channels = channels
+			pruneAnimationSamplers(anim)
+			w.animation(anim)
+		}
+	}
+	markUsedObjects(doc, w, &queue)
+
+	report := &PruneReport{Removed: make(map[string][]uint32)}
+	newIndices := make(map[string][]uint32, len(used))
+	for property, u := range used {
+		indices := make([]uint32, len(u))
+		var n uint32
+		for i, ok := range u {
+			if ok {
+				indices[i] = n
+				n++
+			} else {
+				report.Removed[property] = append(report.Removed[property], uint32(i))
+			}
+		}
+		newIndices[property] = indices
+	}
+	doc.Accessors = keepUsed(doc.Accessors, used[PropertyAccessors])
+	doc.Animations = keepUsed(doc.Animations, used[PropertyAnimations])
+	doc.Buffers = keepUsed(doc.Buffers, used[PropertyBuffers])
+	doc.BufferViews = keepUsed(doc.BufferViews, used[PropertyBufferViews])
+	doc.Cameras = keepUsed(doc.Cameras, used[PropertyCameras])
+	doc.Images = keepUsed(doc.Images, used[PropertyImages])
+	doc.Materials = keepUsed(doc.Materials, used[PropertyMaterials])
+	doc.Meshes = keepUsed(doc.Meshes, used[PropertyMeshes])
+	doc.Nodes = keepUsed(doc.Nodes, used[PropertyNodes])
+	doc.Samplers = keepUsed(doc.Samplers, used[PropertySamplers])
+	doc.Skins = keepUsed(doc.Skins, used[PropertySkins])
+	doc.Textures = keepUsed(doc.Textures, used[PropertyTextures])
+	extBuffers = keepUsed(extBuffers, used[PropertyBuffers])
+
+	remap := func(property string, index uint32) uint32 {
+		if indices, ok := newIndices[property]; ok && int(index) < len(indices) {
+			return indices[index]
+		}
+		return index
+	}
+	remapAll(doc, remap)
+
+	for i, b := range doc.Buffers {
+		if b != nil && !extBuffers[i] {
+			report.BufferBytes += compactBuffer(doc, uint32(i))
+		}
+	}
+	return report, nil
+}
+
+// markUsedObjects marks the objects referenced by the objects in the queue until it is empty.
+func markUsedObjects(doc *Document, w *indexRewriter, queue *[]objectRef) {
+	for len(*queue) > 0 {
+		ref := (*queue)[0]
+		*queue = (*queue)[1:]
+		switch i := ref.index; ref.property {
+		case PropertyAccessors:
+			if acr := doc.Accessors[i]; acr != nil {
+				w.accessor(acr)
+			}
+		case PropertyBuffers:
+			if b := doc.Buffers[i]; b != nil {
+				w.extensions(b.Extensions)
+			}
+		case PropertyBufferViews:
+			if bv := doc.BufferViews[i]; bv != nil {
+				w.bufferView(bv)
+			}
+		case PropertyCameras:
+			if c := doc.Cameras[i]; c != nil {
+				w.camera(c)
+			}
+		case PropertyImages:
+			if im := doc.Images[i]; im != nil {
+				w.image(im)
+			}
+		case PropertyMaterials:
+			if m := doc.Materials[i]; m != nil {
+				w.material(m)
+			}
+		case PropertyMeshes:
+			if m := doc.Meshes[i]; m != nil {
+				w.mesh(m)
+			}
+		case PropertyNodes:
+			if n := doc.Nodes[i]; n != nil {
+				w.node(n)
+			}
+		case PropertySamplers:
+			if s := doc.Samplers[i]; s != nil {
+				w.extensions(s.Extensions)
+			}
+		case PropertyScenes:
+			if s := doc.Scenes[i]; s != nil {
+				w.scene(s)
+			}
+		case PropertySkins:
+			if s := doc.Skins[i]; s != nil {
+				w.skin(s)
+			}
+		case PropertyTextures:
+			if t := doc.Textures[i]; t != nil {
+				w.texture(t)
+			}
+		}
+	}
+}
+
+// pruneAnimationSamplers removes the samplers of anim that are not used by its channels.
+func pruneAnimationSamplers(anim *Animation) {
+	used := make([]bool, len(anim.Samplers))
+	for _, c := range anim.Channels {
+		if c.Sampler != nil && int(*c.Sampler) < len(used) {
+			used[*c.Sampler] = true
+		}
+	}
+	indices := make([]uint32, len(used))
+	var n uint32
+	for i, ok := range used {
+		if ok {
+			indices[i] = n
+			n++
+		}
+	}
+	for _, c := range anim.Channels {
+		if c.Sampler != nil && int(*c.Sampler) < len(used) {
+			*c.Sampler = indices[*c.Sampler]
+		}
+	}
+	anim.Samplers = keepUsed(anim.Samplers, used)
+}
+
+func keepUsed[T any](s []T, used []bool) []T {
+	if s == nil {
+		return nil
+	}
+	kept := make([]T, 0, len(s))
+	for i, v := range s {
+		if used[i] {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// compactBuffer removes the bytes of the buffer at index that are not part of
+// any buffer view, keeping the offsets of the buffer views modulo 4.
+// It returns the number of bytes removed.
+func compactBuffer(doc *Document, index uint32) int {
+	b := doc.Buffers[index]
+	if uint32(len(b.Data)) < b.ByteLength {
+		return 0
+	}
+	var views []*BufferView
+	for _, bv := range doc.BufferViews {
+		if bv != nil && bv.Buffer == index {
+			if uint64(bv.ByteOffset)+uint64(bv.ByteLength) > uint64(b.ByteLength) {
+				return 0
+			}
+			views = append(views, bv)
+		}
+	}
+	if len(views) == 0 {
+		return 0
+	}
+	sort.SliceStable(views, func(i, j int) bool { return views[i].ByteOffset < views[j].ByteOffset })
+
+	var data []byte
+	segStart, segEnd, newStart := uint32(0), uint32(0), uint32(0)
+	offsets := make([]uint32, len(views))
+	for i, bv := range views {
+		start, end := bv.ByteOffset, bv.ByteOffset+bv.ByteLength
+		if i == 0 || start > segEnd {
+			for uint32(len(data))%4 != start%4 {
+				data = append(data, 0)
+			}
+			segStart, segEnd, newStart = start, start, uint32(len(data))
+		}
+		if end > segEnd {
+			data = append(data, b.Data[segEnd:end]...)
+			segEnd = end
+		}
+		offsets[i] = newStart + (start - segStart)
+	}
+	removed := int(b.ByteLength) - len(data)
+	if removed <= 0 {
+		return 0
+	}
+	for i, bv := range views {
+		bv.ByteOffset = offsets[i]
+	}
+	b.Data = data
+	b.ByteLength = uint32(len(data))
+	b.Source = nil
+	if b.IsEmbeddedResource() {
+		b.EmbeddedResource()
+	}
+	return removed
+}
@@ -0,0 +1,174 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestPrune(t *testing.T) {
+	data := make([]byte, 24)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	doc := &Document{
+		Scene:  Index(0),
+		Scenes: []*Scene{{Nodes: []uint32{1}}},
+		Nodes: []*Node{
+			{Name: "orphan", Mesh: Index(0)},
+			{Name: "root", Children: []uint32{2}, Mesh: Index(1)},
+			{Name: "child", Extensions: Extensions{"ref": &testAccessorRef{2}}},
+		},
+		Meshes: []*Mesh{
+			{Name: "unused", Primitives: []*Primitive{{Attributes: Attribute{POSITION: 0}, Material: Index(0)}}},
+			{Name: "used", Primitives: []*Primitive{{Attributes: Attribute{POSITION: 1}, Material: Index(1)}}},
+		},
+		Materials: []*Material{
+			{Name: "unused", PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}}},
+			{Name: "used", PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 1}}},
+		},
+		Textures: []*Texture{{Source: Index(0), Sampler: Index(0)}, {Source: Index(1)}},
+		Samplers: []*Sampler{{}},
+		Images:   []*Image{{URI: "a.png"}, {URI: "b.png"}},
+		Accessors: []*Accessor{
+			{Name: "unused", BufferView: Index(0)},
+			{Name: "used", BufferView: Index(1)},
+			{Name: "ext", BufferView: Index(2)},
+			{Name: "anim", BufferView: Index(2)},
+		},
+		BufferViews: []*BufferView{
+			{Buffer: 0, ByteOffset: 0, ByteLength: 8},
+			{Buffer: 0, ByteOffset: 8, ByteLength: 4},
+			{Buffer: 0, ByteOffset: 14, ByteLength: 6},
+		},
+		Buffers: []*Buffer{{ByteLength: 24, Data: data}, {ByteLength: 4, Data: make([]byte, 4)}},
+		Animations: []*Animation{
+			{
+				Name: "partial",
+				Channels: []*Channel{
+					{Sampler: Index(0), Target: ChannelTarget{Node: Index(0)}},
+					{Sampler: Index(1), Target: ChannelTarget{Node: Index(2)}},
+				},
+				Samplers: []*AnimationSampler{{Input: 0, Output: 0}, {Input: 3, Output: 3}},
+			},
+			{
+				Name:     "orphan",
+				Channels: []*Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0)}}},
+				Samplers: []*AnimationSampler{{Input: 0, Output: 0}},
+			},
+		},
+	}
+	report, err := Prune(doc)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	wantReport := &PruneReport{
+		Removed: map[string][]uint32{
+			PropertyAccessors:   {0},
+			PropertyAnimations:  {1},
+			PropertyBuffers:     {1},
+			PropertyBufferViews: {0},
+			PropertyImages:      {0},
+			PropertyMaterials:   {0},
+			PropertyMeshes:      {0},
+			PropertyNodes:       {0},
+			PropertySamplers:    {0},
+			PropertyTextures:    {0},
+		},
+		BufferBytes: 12,
+	}
+	if diff := deep.Equal(report, wantReport); diff != nil {
+		t.Errorf("Prune() report = %v", diff)
+	}
+	want := &Document{
+		Scene:  Index(0),
+		Scenes: []*Scene{{Nodes: []uint32{0}}},
+		Nodes: []*Node{
+			{Name: "root", Children: []uint32{1}, Mesh: Index(0)},
+			{Name: "child", Extensions: Extensions{"ref": &testAccessorRef{1}}},
+		},
+		Meshes:    []*Mesh{{Name: "used", Primitives: []*Primitive{{Attributes: Attribute{POSITION: 0}, Material: Index(0)}}}},
+		Materials: []*Material{{Name: "used", PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}}}},
+		Textures:  []*Texture{{Source: Index(0)}},
+		Samplers:  []*Sampler{},
+		Images:    []*Image{{URI: "b.png"}},
+		Accessors: []*Accessor{
+			{Name: "used", BufferView: Index(0)},
+			{Name: "ext", BufferView: Index(1)},
+			{Name: "anim", BufferView: Index(1)},
+		},
+		BufferViews: []*BufferView{
+			{Buffer: 0, ByteOffset: 0, ByteLength: 4},
+			{Buffer: 0, ByteOffset: 6, ByteLength: 6},
+		},
+		Buffers: []*Buffer{{ByteLength: 12, Data: []byte{8, 9, 10, 11, 0, 0, 14, 15, 16, 17, 18, 19}}},
+		Animations: []*Animation{{
+			Name:     "partial",
+			Channels: []*Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(1)}}},
+			Samplers: []*AnimationSampler{{Input: 2, Output: 2}},
+		}},
+	}
+	if diff := deep.Equal(doc, want); diff != nil {
+		t.Errorf("Prune() = %v", diff)
+	}
+}
+
+func TestPrune_noScene(t *testing.T) {
+	doc := &Document{
+		Nodes:     []*Node{{Mesh: Index(1)}},
+		Meshes:    []*Mesh{{Name: "unused"}, {Name: "used"}},
+		Materials: []*Material{{}},
+	}
+	report, err := Prune(doc)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if diff := deep.Equal(report.Removed, map[string][]uint32{PropertyMeshes: {0}, PropertyMaterials: {0}}); diff != nil {
+		t.Errorf("Prune() removed = %v", diff)
+	}
+	if len(doc.Nodes) != 1 || *doc.Nodes[0].Mesh != 0 || doc.Meshes[0].Name != "used" {
+		t.Errorf("Prune() = %v", doc)
+	}
+}
+
+func TestPrune_loadError(t *testing.T) {
+	doc := &Document{Buffers: []*Buffer{{ByteLength: 4, Source: bytes.NewReader([]byte{1, 2})}}}
+	if _, err := Prune(doc); err == nil {
+		t.Error("Prune() expected error")
+	}
+	if len(doc.Buffers) != 1 || doc.Buffers[0].Data != nil {
+		t.Error("Prune() modified the document")
+	}
+}
+
+type testTextureRef struct{ Texture *TextureInfo }
+
+func TestPrune_rawExtension(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  Extensions
+	}{
+		{"raw", Extensions{"EXT_unknown": json.RawMessage(`{"indices":1}`)}},
+		{"bytes", Extensions{"EXT_unknown": []byte(`{"indices":1}`)}},
+		{"map", Extensions{"EXT_unknown": map[string]interface{}{"indices": 1}}},
+		{"nested", Extensions{"ref": testTextureRef{&TextureInfo{Extensions: Extensions{"EXT_unknown": json.RawMessage(`{}`)}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				Scenes:    []*Scene{{Nodes: []uint32{0}}},
+				Nodes:     []*Node{{Mesh: Index(0)}},
+				Meshes:    []*Mesh{{Primitives: []*Primitive{{Attributes: Attribute{POSITION: 0}, Extensions: tt.ext}}}},
+				Accessors: []*Accessor{{Name: "position"}, {Name: "ext"}},
+			}
+			if _, err := Prune(doc); err == nil {
+				t.Error("Prune() expected error")
+			}
+			if len(doc.Accessors) != 2 {
+				t.Error("Prune() modified the document")
+			}
+		})
+	}
+}
@@ -0,0 +1,457 @@
+package gltf
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Names of the document properties that can be referenced by index.
+const (
+	PropertyAccessors   = "accessors"
+	PropertyAnimations  = "animations"
+	PropertyBuffers     = "buffers"
+	PropertyBufferViews = "bufferViews"
+	PropertyCameras     = "cameras"
+	PropertyImages      = "images"
+	PropertyMaterials   = "materials"
+	PropertyMeshes      = "meshes"
+	PropertyNodes       = "nodes"
+	PropertySamplers    = "samplers"
+	PropertyScenes      = "scenes"
+	PropertySkins       = "skins"
+	PropertyTextures    = "textures"
+)
+
+// IndexRemapFunc returns the new index of the object at index in the array
+// named property, which is either a document property such as PropertyAccessors
+// or an array defined by an extension, such as "KHR_lights_punctual.lights".
+type IndexRemapFunc func(property string, index uint32) uint32
+
+// An IndexRemapper is implemented by extension payloads that reference objects by index,
+// so that the operations that renumber the objects of a document, like Merge,
+// can keep them consistent.
+//
+// RemapIndices calls remap for each index held by the payload and returns
+// a payload with the indices replaced by the returned values.
+// It must not modify the receiver, so it is usually implemented on a value receiver:
+// when the payload is stored as a pointer and RemapIndices returns a value,
+// a pointer to the value is stored instead.
+// Payloads that don't implement IndexRemapper are kept as is, except the ones
+// that were not decoded, like a json.RawMessage of an unregistered extension:
+// as the indices they hold are unknown, the operations fail instead.
+type IndexRemapper interface {
+	RemapIndices(remap IndexRemapFunc) interface{}
+}
+
+// An ExtensionMerger is implemented by document extension payloads
+// that can be combined when merging documents.
+//
+// MergeExtension returns the combination of the receiver, which belongs to the
+// destination document, and other, which belongs to the merged document and
+// has its indices already remapped. other is never a pointer.
+// It also returns the offset to add to the indices referencing the arrays
+// defined by other, keyed by the property name used in IndexRemapFunc.
+// It must not modify the receiver nor other.
+type ExtensionMerger interface {
+	MergeExtension(other interface{}) (merged interface{}, offsets map[string]uint32, err error)
+}
+
+func remapPayload(v interface{}, remap IndexRemapFunc) interface{} {
+	if r, ok := v.(IndexRemapper); ok {
+		return payloadLike(v, r.RemapIndices(remap))
+	}
+	return v
+}
+
+// opaquePayload reports whether v is an extension payload that was not decoded,
+// so the indices it may hold can't be rewritten.
+func opaquePayload(v interface{}) bool {
+	switch v.(type) {
+	case json.RawMessage, []byte, map[string]interface{}:
+		return true
+	}
+	return false
+}
+
+var extensionsType = reflect.TypeOf(Extensions(nil))
+
+// nestedExtensions calls fn for every non-nil Extensions held by v,
+// such as the extensions of a TextureInfo field of a payload.
+func nestedExtensions(v reflect.Value, fn func(Extensions)) {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if !v.IsNil() {
+			nestedExtensions(v.Elem(), fn)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				nestedExtensions(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			nestedExtensions(v.Index(i), fn)
+		}
+	case reflect.Map:
+		if v.Type() == extensionsType {
+			if !v.IsNil() {
+				fn(v.Interface().(Extensions))
+			}
+			return
+		}
+		for it := v.MapRange(); it.Next(); {
+			nestedExtensions(it.Value(), fn)
+		}
+	}
+}
+
+// payloadLike returns v as a pointer if orig is a pointer to a value of the type of v.
+func payloadLike(orig, v interface{}) interface{} {
+	ot, vt := reflect.TypeOf(orig), reflect.TypeOf(v)
+	if ot == nil || vt == nil || ot.Kind() != reflect.Pointer || ot.Elem() != vt {
+		return v
+	}
+	p := reflect.New(vt)
+	p.Elem().Set(reflect.ValueOf(v))
+	return p.Interface()
+}
+
+// payloadValue returns the value pointed by v if v is a non-nil pointer.
+func payloadValue(v interface{}) interface{} {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		return rv.Elem().Interface()
+	}
+	return v
+}
+
+// RemapIndex returns a pointer to the index rewritten by remap, or nil if index is nil.
+// The value pointed by index is not modified.
+func RemapIndex(remap IndexRemapFunc, property string, index *uint32) *uint32 {
+	if index == nil {
+		return nil
+	}
+	return Index(remap(property, *index))
+}
+
+// RemapTextureInfo returns a copy of info with its texture index
+// and its extensions rewritten by remap, or nil if info is nil.
+func RemapTextureInfo(remap IndexRemapFunc, info *TextureInfo) *TextureInfo {
+	if info == nil {
+		return nil
+	}
+	c := *info
+	c.Index = remap(PropertyTextures, c.Index)
+	c.Extensions = remapExtensions(remap, c.Extensions)
+	return &c
+}
+
+// RemapNormalTexture returns a copy of info with its texture index
+// and its extensions rewritten by remap, or nil if info is nil.
+func RemapNormalTexture(remap IndexRemapFunc, info *NormalTexture) *NormalTexture {
+	if info == nil {
+		return nil
+	}
+	c := *info
+	c.Index = RemapIndex(remap, PropertyTextures, c.Index)
+	c.Extensions = remapExtensions(remap, c.Extensions)
+	return &c
+}
+
+// remapExtensions returns a copy of ext with its payloads rewritten by remap.
+func remapExtensions(remap IndexRemapFunc, ext Extensions) Extensions {
+	if ext == nil {
+		return nil
+	}
+	c := make(Extensions, len(ext))
+	for name, v := range ext {
+		c[name] = remapPayload(v, remap)
+	}
+	return c
+}
+
+// An indexRewriter calls remap for every index of the objects of a document.
+// Unless dryRun is set the indices are replaced in place by the returned values.
+// inExtension is set while remap is called by an extension payload.
+// err records the first extension payload whose indices can't be rewritten.
+// visit, if not nil, is called for every extensions map found in the document,
+// including the ones nested in extension payloads.
+type indexRewriter struct {
+	remap       IndexRemapFunc
+	dryRun      bool
+	inExtension bool
+	err         error
+	visit       func(Extensions)
+}
+
+func (w *indexRewriter) index(property string, index *uint32) {
+	if index != nil {
+		if i := w.remap(property, *index); !w.dryRun {
+			*index = i
+		}
+	}
+}
+
+func (w *indexRewriter) indices(property string, indices []uint32) {
+	for i := range indices {
+		w.index(property, &indices[i])
+	}
+}
+
+func (w *indexRewriter) attributes(attrs Attribute) {
+	for name, index := range attrs {
+		if i := w.remap(PropertyAccessors, index); !w.dryRun {
+			attrs[name] = i
+		}
+	}
+}
+
+func (w *indexRewriter) extensions(ext Extensions) {
+	w.inspect(ext)
+	w.inExtension = true
+	defer func() { w.inExtension = false }()
+	for name, v := range ext {
+		if r, ok := v.(IndexRemapper); ok {
+			if v := r.RemapIndices(w.remap); !w.dryRun {
+				ext[name] = payloadLike(ext[name], v)
+			}
+		}
+	}
+}
+
+// inspect calls visit for ext and the extensions nested in its payloads,
+// and records in err the payloads that were not decoded.
+func (w *indexRewriter) inspect(ext Extensions) {
+	if w.visit != nil && len(ext) > 0 {
+		w.visit(ext)
+	}
+	for name, v := range ext {
+		if opaquePayload(v) {
+			if w.err == nil {
+				w.err = fmt.Errorf("gltf: extension %s payload of type %T can't be remapped", name, v)
+			}
+			continue
+		}
+		nestedExtensions(reflect.ValueOf(v), w.inspect)
+	}
+}
+
+func (w *indexRewriter) textureInfo(info *TextureInfo) {
+	if info != nil {
+		w.index(PropertyTextures, &info.Index)
+		w.extensions(info.Extensions)
+	}
+}
+
+func (w *indexRewriter) accessor(acr *Accessor) {
+	w.index(PropertyBufferViews, acr.BufferView)
+	if acr.Sparse != nil {
+		w.index(PropertyBufferViews, &acr.Sparse.Indices.BufferView)
+		w.index(PropertyBufferViews, &acr.Sparse.Values.BufferView)
+		w.extensions(acr.Sparse.Extensions)
+		w.extensions(acr.Sparse.Indices.Extensions)
+		w.extensions(acr.Sparse.Values.Extensions)
+	}
+	w.extensions(acr.Extensions)
+}
+
+func (w *indexRewriter) animation(anim *Animation) {
+	for _, c := range anim.Channels {
+		w.index(PropertyNodes, c.Target.Node)
+		w.extensions(c.Extensions)
+		w.extensions(c.Target.Extensions)
+	}
+	for _, s := range anim.Samplers {
+		w.index(PropertyAccessors, &s.Input)
+		w.index(PropertyAccessors, &s.Output)
+		w.extensions(s.Extensions)
+	}
+	w.extensions(anim.Extensions)
+}
+
+func (w *indexRewriter) bufferView(bv *BufferView) {
+	w.index(PropertyBuffers, &bv.Buffer)
+	w.extensions(bv.Extensions)
+}
+
+func (w *indexRewriter) camera(c *Camera) {
+	if c.Orthographic != nil {
+		w.extensions(c.Orthographic.Extensions)
+	}
+	if c.Perspective != nil {
+		w.extensions(c.Perspective.Extensions)
+	}
+	w.extensions(c.Extensions)
+}
+
+func (w *indexRewriter) image(im *Image) {
+	w.index(PropertyBufferViews, im.BufferView)
+	w.extensions(im.Extensions)
+}
+
+func (w *indexRewriter) material(m *Material) {
+	if pbr := m.PBRMetallicRoughness; pbr != nil {
+		w.textureInfo(pbr.BaseColorTexture)
+		w.textureInfo(pbr.MetallicRoughnessTexture)
+		w.extensions(pbr.Extensions)
+	}
+	if t := m.NormalTexture; t != nil {
+		w.index(PropertyTextures, t.Index)
+		w.extensions(t.Extensions)
+	}
+	if t := m.OcclusionTexture; t != nil {
+		w.index(PropertyTextures, t.Index)
+		w.extensions(t.Extensions)
+	}
+	w.textureInfo(m.EmissiveTexture)
+	w.extensions(m.Extensions)
+}
+
+func (w *indexRewriter) mesh(mesh *Mesh) {
+	for _, p := range mesh.Primitives {
+		w.attributes(p.Attributes)
+		w.index(PropertyAccessors, p.Indices)
+		w.index(PropertyMaterials, p.Material)
+		for _, target := range p.Targets {
+			w.attributes(target)
+		}
+		w.extensions(p.Extensions)
+	}
+	w.extensions(mesh.Extensions)
+}
+
+func (w *indexRewriter) node(n *Node) {
+	w.index(PropertyCameras, n.Camera)
+	w.indices(PropertyNodes, n.Children)
+	w.index(PropertySkins, n.Skin)
+	w.index(PropertyMeshes, n.Mesh)
+	w.extensions(n.Extensions)
+}
+
+func (w *indexRewriter) scene(s *Scene) {
+	w.indices(PropertyNodes, s.Nodes)
+	w.extensions(s.Extensions)
+}
+
+func (w *indexRewriter) skin(s *Skin) {
+	w.index(PropertyAccessors, s.InverseBindMatrices)
+	w.index(PropertyNodes, s.Skeleton)
+	w.indices(PropertyNodes, s.Joints)
+	w.extensions(s.Extensions)
+}
+
+func (w *indexRewriter) texture(t *Texture) {
+	w.index(PropertySamplers, t.Sampler)
+	w.index(PropertyImages, t.Source)
+	w.extensions(t.Extensions)
+}
+
+// document visits every object of doc, but not the document extensions.
+func (w *indexRewriter) document(doc *Document) {
+	w.index(PropertyScenes, doc.Scene)
+	for _, acr := range doc.Accessors {
+		if acr != nil {
+			w.accessor(acr)
+		}
+	}
+	for _, anim := range doc.Animations {
+		if anim != nil {
+			w.animation(anim)
+		}
+	}
+	for _, b := range doc.Buffers {
+		if b != nil {
+			w.extensions(b.Extensions)
+		}
+	}
+	for _, bv := range doc.BufferViews {
+		if bv != nil {
+			w.bufferView(bv)
+		}
+	}
+	for _, c := range doc.Cameras {
+		if c != nil {
+			w.camera(c)
+		}
+	}
+	for _, im := range doc.Images {
+		if im != nil {
+			w.image(im)
+		}
+	}
+	for _, m := range doc.Materials {
+		if m != nil {
+			w.material(m)
+		}
+	}
+	for _, mesh := range doc.Meshes {
+		if mesh != nil {
+			w.mesh(mesh)
+		}
+	}
+	for _, n := range doc.Nodes {
+		if n != nil {
+			w.node(n)
+		}
+	}
+	for _, s := range doc.Samplers {
+		if s != nil {
+			w.extensions(s.Extensions)
+		}
+	}
+	for _, s := range doc.Scenes {
+		if s != nil {
+			w.scene(s)
+		}
+	}
+	for _, s := range doc.Skins {
+		if s != nil {
+			w.skin(s)
+		}
+	}
+	for _, t := range doc.Textures {
+		if t != nil {
+			w.texture(t)
+		}
+	}
+}
+
+// RemapDocument rewrites in place every index of doc, including the ones held by
+// the extension payloads that implement IndexRemapper.
+// It does not reorder nor remove any object, this is left to the caller.
+// If an extension payload was not decoded, so its indices are unknown,
+// an error is returned without modifying doc.
+func RemapDocument(doc *Document, remap IndexRemapFunc) error {
+	if err := checkRemappable(doc); err != nil {
+		return err
+	}
+	remapAll(doc, remap)
+	return nil
+}
+
+// remapAll is RemapDocument without the check of the extension payloads.
+func remapAll(doc *Document, remap IndexRemapFunc) {
+	remapDocument(doc, remap)
+	for name, v := range doc.Extensions {
+		doc.Extensions[name] = remapPayload(v, remap)
+	}
+}
+
+// checkRemappable returns an error if an extension payload of doc,
+// including the document extensions, was not decoded.
+func checkRemappable(doc *Document) error {
+	w := &indexRewriter{remap: func(_ string, index uint32) uint32 { return index }, dryRun: true}
+	w.document(doc)
+	w.extensions(doc.Extensions)
+	return w.err
+}
+
+// remapDocument rewrites in place every index of doc, including the ones held by
+// extensions of its objects, but not the ones held by the document extensions.
+func remapDocument(doc *Document, remap IndexRemapFunc) {
+	(&indexRewriter{remap: remap}).document(doc)
+}
@@ -0,0 +1,295 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// RepackOptions configures Repack.
+type RepackOptions struct {
+	// MaxBufferSize is the maximum byte length of each buffer, 0 meaning no limit.
+	// A buffer view bigger than the limit is stored alone in a buffer.
+	MaxBufferSize uint32
+	// Interleave stores the vertex attributes of each primitive in a single
+	// buffer view. Attributes that are sparse, shared with other primitives
+	// or whose count differs from the one of the other attributes are kept as is.
+	Interleave bool
+}
+
+// Buffer view groups, in the order they are stored by Repack.
+const (
+	groupVertex = iota
+	groupIndex
+	groupOther
+	groupImage
+)
+
+// maxByteStride is the maximum byteStride allowed by the specification.
+const maxByteStride = 252
+
+// Repack rewrites the content of every buffer view into as few buffers as possible,
+// removing the bytes not used by any buffer view. The buffer views are stored
+// grouped by usage: vertex attributes first, then indices, other data and images,
+// each one starting at an offset multiple of 4.
+//
+// The i-th resulting buffer reuses the i-th buffer of doc, keeping its URI.
+// The content of all the buffers must have been loaded, else Repack fails
+// without modifying doc.
+// Repack fails without modifying doc if an extension payload references a buffer,
+// like EXT_meshopt_compression does, as its byte ranges can't be moved,
+// or if an extension payload was not decoded, as the buffer views it references are unknown.
+func Repack(doc *Document, opts RepackOptions) error {
+	if err := checkRemappable(doc); err != nil {
+		return err
+	}
+	if extensionReferencesBuffers(doc) {
+		return errors.New("gltf: an extension references buffer byte ranges that can't be repacked")
+	}
+	buffers := make([][]byte, len(doc.Buffers))
+	for i, b := range doc.Buffers {
+		if err := b.Load(); err != nil {
+			return err
+		}
+		if uint32(len(b.Data)) < b.ByteLength {
+			return fmt.Errorf("gltf: buffer %d content is not loaded", i)
+		}
+		buffers[i] = b.Data[:b.ByteLength]
+	}
+	if len(doc.BufferViews) == 0 {
+		return nil
+	}
+	data := make([][]byte, len(doc.BufferViews))
+	for i, bv := range doc.BufferViews {
+		if int(bv.Buffer) >= len(buffers) {
+			return fmt.Errorf("gltf: buffer view %d references a missing buffer", i)
+		}
+		if uint64(bv.ByteOffset)+uint64(bv.ByteLength) > uint64(len(buffers[bv.Buffer])) {
+			return fmt.Errorf("gltf: buffer view %d overflows its buffer", i)
+		}
+		data[i] = buffers[bv.Buffer][bv.ByteOffset : bv.ByteOffset+bv.ByteLength]
+	}
+	if opts.Interleave {
+		data = interleaveAttributes(doc, data)
+	}
+
+	groups := bufferViewGroups(doc)
+	order := make([]int, len(doc.BufferViews))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return groups[order[i]] < groups[order[j]] })
+	var packed [][]byte
+	for _, i := range order {
+		cur := len(packed) - 1
+		if cur < 0 || (opts.MaxBufferSize > 0 && len(packed[cur]) > 0 &&
+			uint64(len(padBytes(packed[cur])))+uint64(len(data[i])) > uint64(opts.MaxBufferSize)) {
+			packed = append(packed, nil)
+			cur++
+		}
+		packed[cur] = padBytes(packed[cur])
+		bv := doc.BufferViews[i]
+		bv.Buffer = uint32(cur)
+		bv.ByteOffset = uint32(len(packed[cur]))
+		packed[cur] = append(packed[cur], data[i]...)
+	}
+
+	result := make([]*Buffer, len(packed))
+	for i, d := range packed {
+		b := new(Buffer)
+		if i < len(doc.Buffers) {
+			b = doc.Buffers[i]
+		}
+		b.ByteLength = uint32(len(d))
+		b.Data = d
+		b.Source = nil
+		if b.IsEmbeddedResource() {
+			b.EmbeddedResource()
+		}
+		result[i] = b
+	}
+	doc.Buffers = result
+	return nil
+}
+
+// extensionReferencesBuffers reports whether an extension payload of doc holds a buffer index.
+func extensionReferencesBuffers(doc *Document) bool {
+	var found bool
+	w := &indexRewriter{dryRun: true}
+	w.remap = func(property string, index uint32) uint32 {
+		found = found || (w.inExtension && property == PropertyBuffers)
+		return index
+	}
+	w.document(doc)
+	w.extensions(doc.Extensions)
+	return found
+}
+
+// bufferViewGroups returns the group of each buffer view,
+// based on its target and on the objects that reference it.
+func bufferViewGroups(doc *Document) []int {
+	groups := make([]int, len(doc.BufferViews))
+	for i, bv := range doc.BufferViews {
+		switch bv.Target {
+		case TargetArrayBuffer:
+			groups[i] = groupVertex
+		case TargetElementArrayBuffer:
+			groups[i] = groupIndex
+		default:
+			groups[i] = groupOther
+		}
+	}
+	setAccessorGroup := func(index uint32, group int) {
+		if int(index) < len(doc.Accessors) {
+			if acr := doc.Accessors[index]; acr != nil && acr.BufferView != nil && int(*acr.BufferView) < len(groups) {
+				groups[*acr.BufferView] = group
+			}
+		}
+	}
+	for _, mesh := range doc.Meshes {
+		if mesh == nil {
+			continue
+		}
+		for _, p := range mesh.Primitives {
+			for _, index := range p.Attributes {
+				setAccessorGroup(index, groupVertex)
+			}
+			for _, target := range p.Targets {
+				for _, index := range target {
+					setAccessorGroup(index, groupVertex)
+				}
+			}
+			if p.Indices != nil {
+				setAccessorGroup(*p.Indices, groupIndex)
+			}
+		}
+	}
+	for _, im := range doc.Images {
+		if im != nil && im.BufferView != nil && int(*im.BufferView) < len(groups) {
+			groups[*im.BufferView] = groupImage
+		}
+	}
+	return groups
+}
+
+// interleaveAttributes moves the vertex attributes of each primitive to a new
+// interleaved buffer view, whose content is appended to data, and removes the
+// buffer views that are no longer referenced.
+// It returns the content of the resulting buffer views.
+func interleaveAttributes(doc *Document, data [][]byte) [][]byte {
+	viewRefs := make([]int, len(doc.BufferViews))
+	accessorRefs := make([]int, len(doc.Accessors))
+	w := &indexRewriter{dryRun: true, remap: func(property string, index uint32) uint32 {
+		switch {
+		case property == PropertyBufferViews && int(index) < len(viewRefs):
+			viewRefs[index]++
+		case property == PropertyAccessors && int(index) < len(accessorRefs):
+			accessorRefs[index]++
+		}
+		return index
+	}}
+	w.document(doc)
+	w.extensions(doc.Extensions)
+	unused := make([]bool, len(viewRefs))
+	for i, n := range viewRefs {
+		unused[i] = n == 0
+	}
+
+	for _, mesh := range doc.Meshes {
+		if mesh == nil {
+			continue
+		}
+		for _, p := range mesh.Primitives {
+			names := make([]string, 0, len(p.Attributes))
+			for name := range p.Attributes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			var acrs []*Accessor
+			var stride uint32
+			for _, name := range names {
+				index := p.Attributes[name]
+				if int(index) >= len(doc.Accessors) || accessorRefs[index] != 1 {
+					continue
+				}
+				acr := doc.Accessors[index]
+				if acr == nil || acr.BufferView == nil || acr.Sparse != nil || int(*acr.BufferView) >= len(doc.BufferViews) {
+					continue
+				}
+				if len(acrs) > 0 && acr.Count != acrs[0].Count {
+					continue
+				}
+				size := SizeOfElement(acr.ComponentType, acr.Type)
+				step := doc.BufferViews[*acr.BufferView].ByteStride
+				if step == 0 {
+					step = size
+				}
+				if acr.Count > 0 && uint64(acr.ByteOffset)+uint64(step)*uint64(acr.Count-1)+uint64(size) > uint64(len(data[*acr.BufferView])) {
+					continue
+				}
+				acrs = append(acrs, acr)
+				stride += align4(size)
+			}
+			if len(acrs) < 2 || stride > maxByteStride {
+				continue
+			}
+			count := acrs[0].Count
+			buf := make([]byte, stride*count)
+			var offset uint32
+			for _, acr := range acrs {
+				size := SizeOfElement(acr.ComponentType, acr.Type)
+				step := doc.BufferViews[*acr.BufferView].ByteStride
+				if step == 0 {
+					step = size
+				}
+				src := data[*acr.BufferView][acr.ByteOffset:]
+				for i := uint32(0); i < count; i++ {
+					copy(buf[i*stride+offset:], src[i*step:i*step+size])
+				}
+				viewRefs[*acr.BufferView]--
+				acr.BufferView = Index(uint32(len(doc.BufferViews)))
+				acr.ByteOffset = offset
+				offset += align4(size)
+			}
+			doc.BufferViews = append(doc.BufferViews, &BufferView{
+				ByteLength: uint32(len(buf)),
+				ByteStride: stride,
+				Target:     TargetArrayBuffer,
+			})
+			data = append(data, buf)
+		}
+	}
+
+	indices := make([]uint32, len(doc.BufferViews))
+	var n uint32
+	views := doc.BufferViews[:0]
+	kept := data[:0]
+	for i, bv := range doc.BufferViews {
+		if i < len(viewRefs) && viewRefs[i] == 0 && !unused[i] {
+			continue
+		}
+		indices[i] = n
+		n++
+		views = append(views, bv)
+		kept = append(kept, data[i])
+	}
+	if int(n) == len(indices) {
+		return data
+	}
+	for i := len(views); i < len(doc.BufferViews); i++ {
+		doc.BufferViews[i] = nil
+	}
+	doc.BufferViews = views
+	remapAll(doc, func(property string, index uint32) uint32 {
+		if property == PropertyBufferViews && int(index) < len(indices) {
+			return indices[index]
+		}
+		return index
+	})
+	return kept
+}
+
+// align4 rounds n up to a multiple of 4.
+func align4(n uint32) uint32 {
+	return (n + 3) &^ 3
+}
@@ -0,0 +1,145 @@
+package gltf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type testBufferRef struct{ Buffer uint32 }
+
+func (b testBufferRef) RemapIndices(remap IndexRemapFunc) interface{} {
+	return testBufferRef{remap(PropertyBuffers, b.Buffer)}
+}
+
+func TestRepack(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    RepackOptions
+		modify  func(doc *Document)
+		wantErr bool
+		check   func(t *testing.T, doc *Document)
+	}{
+		{"base", RepackOptions{}, nil, false, func(t *testing.T, doc *Document) {
+			wantViews := []*BufferView{
+				{Buffer: 0, ByteOffset: 24, ByteLength: 3},
+				{Buffer: 0, ByteOffset: 16, ByteLength: 6},
+				{Buffer: 0, ByteOffset: 0, ByteLength: 8, ByteStride: 4},
+				{Buffer: 0, ByteOffset: 8, ByteLength: 8},
+			}
+			if diff := deep.Equal(doc.BufferViews, wantViews); diff != nil {
+				t.Errorf("Repack() buffer views = %v", diff)
+			}
+			wantBuffers := []*Buffer{{URI: "a.bin", ByteLength: 27, Data: []byte{
+				10, 11, 12, 0, 13, 14, 15, 0,
+				20, 21, 0, 0, 22, 23, 0, 0,
+				4, 0, 5, 0, 6, 0, 0, 0,
+				1, 2, 3,
+			}}}
+			if diff := deep.Equal(doc.Buffers, wantBuffers); diff != nil {
+				t.Errorf("Repack() buffers = %v", diff)
+			}
+		}},
+		{"maxBufferSize", RepackOptions{MaxBufferSize: 12}, nil, false, func(t *testing.T, doc *Document) {
+			if len(doc.Buffers) != 3 {
+				t.Fatalf("Repack() buffers = %d, want 3", len(doc.Buffers))
+			}
+			for i, want := range []uint32{8, 8, 11} {
+				if got := doc.Buffers[i].ByteLength; got != want {
+					t.Errorf("Repack() buffer %d length = %d, want %d", i, got, want)
+				}
+			}
+			if doc.Buffers[0].URI != "a.bin" || doc.Buffers[1].URI != "" {
+				t.Error("Repack() expected buffers to be reused")
+			}
+			if got := doc.BufferViews[0]; got.Buffer != 2 || got.ByteOffset != 8 {
+				t.Errorf("Repack() image view = %+v", got)
+			}
+		}},
+		{"interleave", RepackOptions{Interleave: true}, nil, false, func(t *testing.T, doc *Document) {
+			wantViews := []*BufferView{
+				{Buffer: 0, ByteOffset: 24, ByteLength: 3},
+				{Buffer: 0, ByteOffset: 16, ByteLength: 6},
+				{Buffer: 0, ByteOffset: 0, ByteLength: 16, ByteStride: 8, Target: TargetArrayBuffer},
+			}
+			if diff := deep.Equal(doc.BufferViews, wantViews); diff != nil {
+				t.Errorf("Repack() buffer views = %v", diff)
+			}
+			wantAccessors := []*Accessor{
+				{BufferView: Index(1), ComponentType: ComponentUshort, Type: AccessorScalar, Count: 3},
+				{BufferView: Index(2), ComponentType: ComponentByte, Type: AccessorVec3, Count: 2},
+				{BufferView: Index(2), ByteOffset: 4, ComponentType: ComponentUbyte, Type: AccessorVec2, Count: 2},
+			}
+			if diff := deep.Equal(doc.Accessors, wantAccessors); diff != nil {
+				t.Errorf("Repack() accessors = %v", diff)
+			}
+			wantData := []byte{
+				10, 11, 12, 0, 20, 21, 0, 0,
+				13, 14, 15, 0, 22, 23, 0, 0,
+				4, 0, 5, 0, 6, 0, 0, 0,
+				1, 2, 3,
+			}
+			if diff := deep.Equal(doc.Buffers[0].Data, wantData); diff != nil {
+				t.Errorf("Repack() data = %v", diff)
+			}
+			if doc.Images[0].BufferView == nil || *doc.Images[0].BufferView != 0 {
+				t.Errorf("Repack() image buffer view = %v", doc.Images[0].BufferView)
+			}
+		}},
+		{"notLoaded", RepackOptions{}, func(doc *Document) { doc.Buffers[1].Data = nil }, true, nil},
+		{"bufferExtension", RepackOptions{}, func(doc *Document) {
+			doc.BufferViews[2].Extensions = Extensions{"EXT_meshopt_compression": testBufferRef{1}}
+		}, true, nil},
+		{"rawExtension", RepackOptions{}, func(doc *Document) {
+			doc.BufferViews[2].Extensions = Extensions{"EXT_meshopt_compression": json.RawMessage(`{"buffer":1}`)}
+		}, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				Buffers: []*Buffer{
+					{URI: "a.bin", ByteLength: 16, Data: []byte{
+						1, 2, 3, // image
+						0, 0, 0, 0, 0, // dead range
+						4, 0, 5, 0, 6, 0, // indices
+						0, 0,
+					}},
+					{ByteLength: 18, Data: []byte{
+						10, 11, 12, 0, 13, 14, 15, 0, // positions, 2 x [3]int8 aligned to 4
+						20, 21, 0, 0, 22, 23, 0, 0, // texcoords, 2 x [2]uint8 aligned to 4
+						30, 31,
+					}},
+				},
+				BufferViews: []*BufferView{
+					{Buffer: 0, ByteOffset: 0, ByteLength: 3},
+					{Buffer: 0, ByteOffset: 8, ByteLength: 6},
+					{Buffer: 1, ByteOffset: 0, ByteLength: 8, ByteStride: 4},
+					{Buffer: 1, ByteOffset: 8, ByteLength: 8},
+				},
+				Accessors: []*Accessor{
+					{BufferView: Index(1), ComponentType: ComponentUshort, Type: AccessorScalar, Count: 3},
+					{BufferView: Index(2), ComponentType: ComponentByte, Type: AccessorVec3, Count: 2},
+					{BufferView: Index(3), ComponentType: ComponentUbyte, Type: AccessorVec2, Count: 2},
+				},
+				Meshes: []*Mesh{{Primitives: []*Primitive{{
+					Attributes: Attribute{POSITION: 1, TEXCOORD_0: 2},
+					Indices:    Index(0),
+				}}}},
+				Images: []*Image{{BufferView: Index(0), MimeType: "image/png"}},
+			}
+			if tt.modify != nil {
+				tt.modify(doc)
+			}
+			if err := Repack(doc, tt.opts); (err != nil) != tt.wantErr {
+				t.Fatalf("Repack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && (doc.BufferViews[2].Buffer != 1 || len(doc.Buffers) != 2) {
+				t.Error("Repack() modified the document")
+			}
+			if tt.check != nil {
+				tt.check(t, doc)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package gltf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// A ResourceResolver opens the external resource referenced by uri.
+// The caller reads at most the bytes it expects and closes the returned reader.
+//
+// The Decoder uses it for absolute URIs, such as http://, file:// or
+// object store URIs, and for relative URIs when Decoder.Fsys is nil.
+type ResourceResolver interface {
+	ReadResource(uri string) (io.ReadCloser, error)
+}
+
+// A ResourceWriter writes the content of the external resource referenced by uri.
+//
+// The Encoder uses it for absolute URIs and for relative URIs when Encoder.Fsys is nil.
+type ResourceWriter interface {
+	WriteResource(uri string, data []byte) error
+}
+
+// HTTPResolver is a ResourceResolver and a ResourceWriter for http and https URIs.
+//
+// Resources are read with GET requests and written with PUT requests.
+// If Client is nil http.DefaultClient is used.
+type HTTPResolver struct {
+	Client *http.Client
+}
+
+func (r *HTTPResolver) client() *http.Client {
+	if r.Client == nil {
+		return http.DefaultClient
+	}
+	return r.Client
+}
+
+// ReadResource fetches the resource referenced by uri and returns the response body.
+func (r *HTTPResolver) ReadResource(uri string) (io.ReadCloser, error) {
+	if err := checkHTTPScheme(uri); err != nil {
+		return nil, err
+	}
+	resp, err := r.client().Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("gltf: GET %s: %s", uri, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// WriteResource uploads data to the resource referenced by uri.
+func (r *HTTPResolver) WriteResource(uri string, data []byte) error {
+	if err := checkHTTPScheme(uri); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, uri, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("gltf: PUT %s: %s", uri, resp.Status)
+	}
+	return nil
+}
+
+// FileResolver is a ResourceResolver for file URIs, such as file:///models/a.bin,
+// which are read from the local file system.
+// Only URIs without host, or with the localhost host, are supported.
+type FileResolver struct{}
+
+// ReadResource opens the local file referenced by uri.
+func (FileResolver) ReadResource(uri string) (io.ReadCloser, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("gltf: unsupported uri scheme '%s'", u.Scheme)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, fmt.Errorf("gltf: unsupported file uri host '%s'", u.Host)
+	}
+	return os.Open(filepath.FromSlash(u.Path))
+}
+
+func checkHTTPScheme(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("gltf: unsupported uri scheme '%s'", u.Scheme)
+	}
+	return nil
+}
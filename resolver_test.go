@@ -0,0 +1,148 @@
+package gltf
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type memResolver struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (m *memResolver) get(uri string) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.files[uri]
+}
+
+func (m *memResolver) ReadResource(uri string) (io.ReadCloser, error) {
+	data := m.get(uri)
+	if data == nil {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memResolver) WriteResource(uri string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[uri] = data
+	return nil
+}
+
+func newResourceServer(t *testing.T) (*httptest.Server, *memResolver) {
+	store := &memResolver{files: map[string][]byte{
+		"/a.bin": []byte("abcdef"),
+		"/a.png": []byte("png"),
+	}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			data := store.get(r.URL.Path)
+			if data == nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			store.WriteResource(r.URL.Path, data)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+func TestDecoder_Decode_resolver(t *testing.T) {
+	srv, _ := newResourceServer(t)
+	file := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(file, []byte("abcdef"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		uri      string
+		want     []byte
+		wantErr  bool
+		resolver ResourceResolver
+	}{
+		{"http", srv.URL + "/a.bin", []byte("abc"), false, &HTTPResolver{Client: srv.Client()}},
+		{"notFound", srv.URL + "/b.bin", nil, true, &HTTPResolver{Client: srv.Client()}},
+		{"scheme", "ftp://a.bin", nil, true, &HTTPResolver{Client: srv.Client()}},
+		{"noResolver", srv.URL + "/a.bin", nil, true, nil},
+		{"file", "file://" + filepath.ToSlash(file), []byte("abc"), false, FileResolver{}},
+		{"fileHost", "file://host" + filepath.ToSlash(file), nil, true, FileResolver{}},
+		{"fileScheme", srv.URL + "/a.bin", nil, true, FileResolver{}},
+		{"custom", "s3://bucket/a.bin", []byte("abc"), false, &memResolver{files: map[string][]byte{"s3://bucket/a.bin": []byte("abc")}}},
+		{"relative", "a.bin", []byte("abc"), false, &memResolver{files: map[string][]byte{"a.bin": []byte("abc")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoder(strings.NewReader(`{"buffers": [{"byteLength": 3, "uri": "` + tt.uri + `"}]}`))
+			d.Resolver = tt.resolver
+			doc := new(Document)
+			if err := d.Decode(doc); (err != nil) != tt.wantErr {
+				t.Fatalf("Decoder.Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(doc.Buffers[0].Data, tt.want) {
+				t.Errorf("Decoder.Decode() buffer = %s, want %s", doc.Buffers[0].Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_loadImages(t *testing.T) {
+	srv, _ := newResourceServer(t)
+	data := `{"images": [{"uri": "` + srv.URL + `/a.png"}, {"uri": "data:image/png;base64,cG5n"}, {"bufferView": 0, "mimeType": "image/png"}]}`
+	d := NewDecoder(strings.NewReader(data))
+	d.Resolver = &HTTPResolver{Client: srv.Client()}
+	d.LoadImages = true
+	doc := new(Document)
+	if err := d.Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	for i, want := range [][]byte{[]byte("png"), []byte("png"), nil} {
+		if got := doc.Images[i].Data; !reflect.DeepEqual(got, want) {
+			t.Errorf("Decoder.Decode() image %d = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestEncoder_Encode_resourceWriter(t *testing.T) {
+	srv, store := newResourceServer(t)
+	doc := &Document{
+		Buffers: []*Buffer{{ByteLength: 3, URI: srv.URL + "/c.bin", Data: []byte("abc")}},
+		Images:  []*Image{{URI: srv.URL + "/c.png", Data: []byte("png")}, {URI: "data:image/png;base64,cG5n", Data: []byte("png")}},
+	}
+	e := NewEncoder(new(bytes.Buffer))
+	e.AsBinary = false
+	e.ResourceWriter = &HTTPResolver{Client: srv.Client()}
+	if err := e.Encode(doc); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	for uri, want := range map[string][]byte{"/c.bin": []byte("abc"), "/c.png": []byte("png")} {
+		if got := store.get(uri); !reflect.DeepEqual(got, want) {
+			t.Errorf("Encoder.Encode() %s = %s, want %s", uri, got, want)
+		}
+	}
+	e.ResourceWriter = &HTTPResolver{}
+	doc.Buffers[0].URI = "ftp://c.bin"
+	if err := e.Encode(doc); err == nil {
+		t.Error("Encoder.Encode() expected error")
+	}
+}
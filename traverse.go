@@ -0,0 +1,258 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// SkipChildren is used as a return value from a WalkFunc to indicate
+	// that the children of the node are not to be visited.
+	SkipChildren = errors.New("skip children")
+
+	// ErrNodeCycle is returned when a node is one of its own ancestors.
+	ErrNodeCycle = errors.New("gltf: node hierarchy contains a cycle")
+
+	// ErrMultipleParents is returned when a node is the child of more than one node,
+	// or is both a child and a scene root.
+	ErrMultipleParents = errors.New("gltf: node has multiple parents")
+)
+
+// WalkFunc is the type of the function called for each node visited by
+// WalkDepthFirst and WalkBreadthFirst.
+//
+// path contains the indices of the ancestors of the node, from the root
+// to its parent, and is only valid during the call.
+// If the function returns SkipChildren the children of the node are not visited,
+// any other error stops the traversal and is returned by the walk function.
+type WalkFunc func(index uint32, n *Node, path []uint32) error
+
+// WalkDepthFirst visits the hierarchies rooted at roots in depth-first order,
+// calling fn for each node before its children.
+func WalkDepthFirst(doc *Document, roots []uint32, fn WalkFunc) error {
+	w := newWalker(doc)
+	var visit func(index uint32, path []uint32) error
+	visit = func(index uint32, path []uint32) error {
+		n, err := w.enter(index, path)
+		if err != nil {
+			return err
+		}
+		if err = fn(index, n, path); err == SkipChildren {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		path = append(path, index)
+		for _, child := range n.Children {
+			if err := visit(child, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, root := range roots {
+		if err := visit(root, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkBreadthFirst visits the hierarchies rooted at roots in breadth-first order,
+// calling fn for all the nodes of a depth before the nodes of the next one.
+func WalkBreadthFirst(doc *Document, roots []uint32, fn WalkFunc) error {
+	type item struct {
+		index uint32
+		path  []uint32
+	}
+	w := newWalker(doc)
+	queue := make([]item, len(roots))
+	for i, root := range roots {
+		queue[i] = item{index: root}
+	}
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+		n, err := w.enter(it.index, it.path)
+		if err != nil {
+			return err
+		}
+		if err = fn(it.index, n, it.path); err == SkipChildren {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if len(n.Children) == 0 {
+			continue
+		}
+		path := make([]uint32, len(it.path)+1)
+		copy(path, it.path)
+		path[len(it.path)] = it.index
+		for _, child := range n.Children {
+			queue = append(queue, item{index: child, path: path})
+		}
+	}
+	return nil
+}
+
+type walker struct {
+	doc     *Document
+	visited []bool
+}
+
+func newWalker(doc *Document) *walker {
+	return &walker{doc: doc, visited: make([]bool, len(doc.Nodes))}
+}
+
+// enter marks the node as visited, failing if it does not exist
+// or if it has already been reached through another path.
+func (w *walker) enter(index uint32, path []uint32) (*Node, error) {
+	if int(index) >= len(w.doc.Nodes) || w.doc.Nodes[index] == nil {
+		return nil, fmt.Errorf("gltf: node %d does not exist", index)
+	}
+	if w.visited[index] {
+		for _, p := range path {
+			if p == index {
+				return nil, fmt.Errorf("%w: node %d", ErrNodeCycle, index)
+			}
+		}
+		return nil, fmt.Errorf("%w: node %d", ErrMultipleParents, index)
+	}
+	w.visited[index] = true
+	return w.doc.Nodes[index], nil
+}
+
+// Parents returns the parent of each node that is the child of another node.
+// Root nodes are not in the map.
+func Parents(doc *Document) (map[uint32]uint32, error) {
+	parents := make(map[uint32]uint32)
+	for i, n := range doc.Nodes {
+		if n == nil {
+			continue
+		}
+		for _, child := range n.Children {
+			if int(child) >= len(doc.Nodes) {
+				return nil, fmt.Errorf("gltf: node %d does not exist", child)
+			}
+			if _, ok := parents[child]; ok {
+				return nil, fmt.Errorf("%w: node %d", ErrMultipleParents, child)
+			}
+			parents[child] = uint32(i)
+		}
+	}
+	for _, s := range doc.Scenes {
+		if s == nil {
+			continue
+		}
+		for _, root := range s.Nodes {
+			if _, ok := parents[root]; ok {
+				return nil, fmt.Errorf("%w: node %d", ErrMultipleParents, root)
+			}
+		}
+	}
+	// Walk up from every node, marking the ancestors being walked and the ones
+	// already known to lead to a root, so each node is only walked once.
+	const (
+		walking = 1
+		rooted  = 2
+	)
+	state := make([]uint8, len(doc.Nodes))
+	for start := range doc.Nodes {
+		for index, ok := uint32(start), true; ok && state[index] != rooted; index, ok = parents[index] {
+			if state[index] == walking {
+				return nil, fmt.Errorf("%w: node %d", ErrNodeCycle, index)
+			}
+			state[index] = walking
+		}
+		for index, ok := uint32(start), true; ok && state[index] == walking; index, ok = parents[index] {
+			state[index] = rooted
+		}
+	}
+	return parents, nil
+}
+
+// NodePath returns the indices of the nodes from the root of the hierarchy
+// containing the node at index down to the node itself.
+func NodePath(doc *Document, index uint32) ([]uint32, error) {
+	parents, err := Parents(doc)
+	if err != nil {
+		return nil, err
+	}
+	return nodePath(doc, parents, index)
+}
+
+func nodePath(doc *Document, parents map[uint32]uint32, index uint32) ([]uint32, error) {
+	if int(index) >= len(doc.Nodes) {
+		return nil, fmt.Errorf("gltf: node %d does not exist", index)
+	}
+	path := []uint32{index}
+	for {
+		parent, ok := parents[index]
+		if !ok {
+			break
+		}
+		path = append(path, parent)
+		index = parent
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// WorldMatrix returns the transform of the node at index relative to the scene,
+// which is the product of the local matrices of the node and all its ancestors.
+// It computes the parents of every node, so to transform many nodes
+// use WorldMatrices or NodeWorldMatrix instead.
+func WorldMatrix(doc *Document, index uint32) ([16]float32, error) {
+	parents, err := Parents(doc)
+	if err != nil {
+		return DefaultMatrix, err
+	}
+	return NodeWorldMatrix(doc, parents, index)
+}
+
+// NodeWorldMatrix is like WorldMatrix but looks up the ancestors of the node
+// in parents, as returned by Parents, instead of computing them.
+func NodeWorldMatrix(doc *Document, parents map[uint32]uint32, index uint32) ([16]float32, error) {
+	path, err := nodePath(doc, parents, index)
+	if err != nil {
+		return DefaultMatrix, err
+	}
+	m := DefaultMatrix
+	for _, i := range path {
+		if n := doc.Nodes[i]; n != nil {
+			m = mulMatrix(m, n.LocalMatrix())
+		}
+	}
+	return m, nil
+}
+
+// WorldMatrices returns the world transform of every node, in the same order as doc.Nodes.
+func WorldMatrices(doc *Document) ([][16]float32, error) {
+	parents, err := Parents(doc)
+	if err != nil {
+		return nil, err
+	}
+	world := make([][16]float32, len(doc.Nodes))
+	done := make([]bool, len(doc.Nodes))
+	var compute func(index uint32) [16]float32
+	compute = func(index uint32) [16]float32 {
+		if done[index] {
+			return world[index]
+		}
+		m := DefaultMatrix
+		if n := doc.Nodes[index]; n != nil {
+			m = n.LocalMatrix()
+		}
+		if parent, ok := parents[index]; ok {
+			m = mulMatrix(compute(parent), m)
+		}
+		world[index], done[index] = m, true
+		return m
+	}
+	for i := range doc.Nodes {
+		compute(uint32(i))
+	}
+	return world, nil
+}
@@ -0,0 +1,161 @@
+package gltf
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type visit struct {
+	index uint32
+	path  []uint32
+}
+
+func TestWalk(t *testing.T) {
+	tests := []struct {
+		name string
+		walk func(*Document, []uint32, WalkFunc) error
+		want []visit
+	}{
+		{"depthFirst", WalkDepthFirst, []visit{{0, nil}, {1, []uint32{0}}, {3, []uint32{0, 1}}, {2, []uint32{0}}, {4, nil}}},
+		{"breadthFirst", WalkBreadthFirst, []visit{{0, nil}, {4, nil}, {1, []uint32{0}}, {2, []uint32{0}}, {3, []uint32{0, 1}}}},
+	}
+	// The hierarchy is 0 -> (1 -> 3, 2), 4.
+	doc := &Document{
+		Scenes: []*Scene{{Nodes: []uint32{0, 4}}},
+		Nodes:  []*Node{{Children: []uint32{1, 2}}, {Children: []uint32{3}}, {}, {}, {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []visit
+			err := tt.walk(doc, doc.Scenes[0].Nodes, func(index uint32, n *Node, path []uint32) error {
+				if n != doc.Nodes[index] {
+					t.Errorf("node %d = %v, want %v", index, n, doc.Nodes[index])
+				}
+				got = append(got, visit{index, append([]uint32(nil), path...)})
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("walk error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("walk = %v, want %v", got, tt.want)
+			}
+
+			got = nil
+			err = tt.walk(doc, doc.Scenes[0].Nodes, func(index uint32, n *Node, path []uint32) error {
+				got = append(got, visit{index, append([]uint32(nil), path...)})
+				if index == 1 {
+					return SkipChildren
+				}
+				return nil
+			})
+			if err != nil || len(got) != 4 {
+				t.Errorf("walk with SkipChildren = %v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestWalk_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []*Node
+		roots []uint32
+		want  error
+	}{
+		{"cycle", []*Node{{Children: []uint32{1}}, {Children: []uint32{0}}}, []uint32{0}, ErrNodeCycle},
+		{"multipleParents", []*Node{{Children: []uint32{2}}, {Children: []uint32{2}}, {}}, []uint32{0, 1}, ErrMultipleParents},
+		{"childRoot", []*Node{{Children: []uint32{1}}, {}}, []uint32{0, 1}, ErrMultipleParents},
+		{"missing", []*Node{{Children: []uint32{3}}}, []uint32{0}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Nodes: tt.nodes}
+			noop := func(uint32, *Node, []uint32) error { return nil }
+			for _, walk := range []func(*Document, []uint32, WalkFunc) error{WalkDepthFirst, WalkBreadthFirst} {
+				err := walk(doc, tt.roots, noop)
+				if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+					t.Errorf("walk error = %v, want %v", err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParents(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(doc *Document)
+		wantErr error
+	}{
+		{"valid", func(doc *Document) {}, nil},
+		{"childRoot", func(doc *Document) { doc.Scenes = append(doc.Scenes, &Scene{Nodes: []uint32{1}}) }, ErrMultipleParents},
+		{"multipleParents", func(doc *Document) { doc.Nodes[3].Children = []uint32{1} }, ErrMultipleParents},
+		{"cycle", func(doc *Document) { doc.Nodes[0].Children = []uint32{2}; doc.Nodes[3].Children = []uint32{1} }, ErrNodeCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The hierarchy is 0 -> (1 -> 3, 2), 4.
+			doc := &Document{
+				Scenes: []*Scene{{Nodes: []uint32{0, 4}}},
+				Nodes: []*Node{
+					{Children: []uint32{1, 2}, Translation: [3]float32{1, 0, 0}},
+					{Children: []uint32{3}, Rotation: [4]float32{0, 0, float32(math.Sqrt2 / 2), float32(math.Sqrt2 / 2)}},
+					{Scale: [3]float32{2, 2, 2}},
+					{Translation: [3]float32{0, 1, 0}},
+					{Matrix: [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 5, 6, 7, 1}},
+				},
+			}
+			tt.modify(doc)
+			parents, err := Parents(doc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parents() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := WorldMatrix(doc, 3); !errors.Is(err, tt.wantErr) {
+				t.Errorf("WorldMatrix() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if want := map[uint32]uint32{1: 0, 2: 0, 3: 1}; !reflect.DeepEqual(parents, want) {
+				t.Errorf("Parents() = %v, want %v", parents, want)
+			}
+			path, err := NodePath(doc, 3)
+			if err != nil || !reflect.DeepEqual(path, []uint32{0, 1, 3}) {
+				t.Errorf("NodePath() = %v, %v", path, err)
+			}
+			all, err := WorldMatrices(doc)
+			if err != nil {
+				t.Fatalf("WorldMatrices() error = %v", err)
+			}
+			want := map[uint32][16]float32{
+				0: {1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 0, 0, 1},
+				2: {2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 1, 0, 0, 1},
+				3: {0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1},
+				4: doc.Nodes[4].Matrix,
+			}
+			for index, want := range want {
+				if got, err := WorldMatrix(doc, index); err != nil || !matrixAlmostEqual(got, want) {
+					t.Errorf("WorldMatrix(%d) = %v, %v, want %v", index, got, err, want)
+				}
+				if got, err := NodeWorldMatrix(doc, parents, index); err != nil || !matrixAlmostEqual(got, want) {
+					t.Errorf("NodeWorldMatrix(%d) = %v, %v, want %v", index, got, err, want)
+				}
+				if !matrixAlmostEqual(all[index], want) {
+					t.Errorf("WorldMatrices()[%d] = %v, want %v", index, all[index], want)
+				}
+			}
+		})
+	}
+}
+
+func matrixAlmostEqual(a, b [16]float32) bool {
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > 1e-6 {
+			return false
+		}
+	}
+	return true
+}
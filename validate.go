@@ -0,0 +1,778 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity defines how serious a validation Issue is.
+type Severity uint8
+
+const (
+	// SeverityError marks a violation of the glTF specification.
+	SeverityError Severity = iota
+	// SeverityWarning marks a valid but suspicious construct.
+	SeverityWarning
+)
+
+// String returns the lowercase name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "Severity(" + strconv.Itoa(int(s)) + ")"
+}
+
+// An Issue is a single problem found by Document.Validate.
+type Issue struct {
+	Path     string   // JSON pointer (RFC 6901) to the offending value, e.g. "/accessors/2/count".
+	Severity Severity // How serious the issue is.
+	Message  string   // Human readable description.
+}
+
+// String returns the issue formatted as "severity path: message".
+func (is Issue) String() string {
+	return is.Severity.String() + " " + is.Path + ": " + is.Message
+}
+
+// Issues is the list of problems found by Document.Validate.
+type Issues []Issue
+
+// HasErrors returns true if any of the issues has SeverityError.
+func (is Issues) HasErrors() bool {
+	for _, i := range is {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns an error summarizing all the SeverityError issues,
+// or nil if there are none.
+func (is Issues) Err() error {
+	var msgs []string
+	for _, i := range is {
+		if i.Severity == SeverityError {
+			msgs = append(msgs, i.Path+": "+i.Message)
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New("gltf: invalid document: " + strings.Join(msgs, "; "))
+}
+
+// Validate checks doc against the rules declared in the `validate` struct tags
+// and against the referential integrity constraints of the glTF specification:
+// index ranges, accessor and buffer view bounds, node hierarchy cycles,
+// skins and animation channels.
+//
+// The returned issues are sorted by path. A document is valid if
+// none of them has SeverityError, see Issues.Err.
+func (doc *Document) Validate() Issues {
+	v := &validator{doc: doc}
+	v.walk("", reflect.ValueOf(doc))
+	v.validateExtensionsRequired()
+	v.validateScenes()
+	v.validateNodes()
+	v.validateBuffers()
+	v.validateBufferViews()
+	v.validateAccessors()
+	v.validateMeshes()
+	v.validateMaterials()
+	v.validateTextures()
+	v.validateImages()
+	v.validateCameras()
+	v.validateSkins()
+	v.validateAnimations()
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		return v.issues[i].Path < v.issues[j].Path
+	})
+	return v.issues
+}
+
+type validator struct {
+	doc    *Document
+	issues Issues
+}
+
+func (v *validator) errorf(path string, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{Path: path, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(path string, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{Path: path, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+func pointer(path string, tokens ...interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(path)
+	for _, t := range tokens {
+		sb.WriteByte('/')
+		switch t := t.(type) {
+		case string:
+			sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(t))
+		default:
+			fmt.Fprint(&sb, t)
+		}
+	}
+	return sb.String()
+}
+
+// checkIndex reports an error if index is not lower than length.
+func (v *validator) checkIndex(path string, index uint32, length int, kind string) bool {
+	if int(index) >= length {
+		v.errorf(path, "%s index %d out of range [0, %d)", kind, index, length)
+		return false
+	}
+	return true
+}
+
+func (v *validator) checkOptionalIndex(path string, index *uint32, length int, kind string) bool {
+	if index == nil {
+		return false
+	}
+	return v.checkIndex(path, *index, length, kind)
+}
+
+// walk descends into val validating the tagged fields of every struct found.
+func (v *validator) walk(path string, val reflect.Value) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		tp := val.Type()
+		if tp.PkgPath() != reflect.TypeOf(Document{}).PkgPath() {
+			return
+		}
+		for i := 0; i < tp.NumField(); i++ {
+			f := tp.Field(i)
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" || name == "" || name == "extensions" || name == "extras" {
+				continue
+			}
+			fpath := pointer(path, name)
+			if tag := f.Tag.Get("validate"); tag != "" {
+				v.checkRules(fpath, val.Field(i), val, strings.Split(tag, ","))
+			} else {
+				v.walk(fpath, val.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if val.Type().Elem().Kind() != reflect.Ptr && val.Type().Elem().Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < val.Len(); i++ {
+			v.walk(pointer(path, i), val.Index(i))
+		}
+	}
+}
+
+// checkRules evaluates the subset of the go-playground/validator syntax used in this package.
+func (v *validator) checkRules(path string, val, parent reflect.Value, rules []string) {
+	for i, rule := range rules {
+		switch rule {
+		case "":
+			continue
+		case "omitempty":
+			if isEmptyValue(val) {
+				return
+			}
+			continue
+		case "required":
+			if isEmptyValue(val) {
+				v.errorf(path, "value is required")
+				return
+			}
+			continue
+		}
+		for val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return
+			}
+			val = val.Elem()
+		}
+		if rule == "dive" {
+			v.dive(path, val, rules[i+1:])
+			return
+		}
+		if !v.checkRule(path, val, parent, rule) {
+			return
+		}
+	}
+	v.walk(path, val)
+}
+
+func (v *validator) dive(path string, val reflect.Value, rules []string) {
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			v.checkRules(pointer(path, i), val.Index(i), reflect.Value{}, rules)
+		}
+	case reflect.Map:
+		var keyRules, valueRules []string
+		for i := 0; i < len(rules); i++ {
+			if rules[i] == "keys" {
+				for i++; i < len(rules) && rules[i] != "endkeys"; i++ {
+					keyRules = append(keyRules, rules[i])
+				}
+				continue
+			}
+			valueRules = append(valueRules, rules[i])
+		}
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, k := range keys {
+			kpath := pointer(path, fmt.Sprint(k))
+			v.checkRules(kpath, k, reflect.Value{}, keyRules)
+			v.checkRules(kpath, val.MapIndex(k), reflect.Value{}, valueRules)
+		}
+	}
+}
+
+func (v *validator) checkRule(path string, val, parent reflect.Value, rule string) bool {
+	op, param, _ := strings.Cut(rule, "=")
+	switch op {
+	case "unique":
+		seen := make(map[interface{}]struct{}, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			e := val.Index(i).Interface()
+			if _, ok := seen[e]; ok {
+				v.errorf(path, "value %v is duplicated", e)
+				return false
+			}
+			seen[e] = struct{}{}
+		}
+		return true
+	case "oneof":
+		got := fmt.Sprint(val.Interface())
+		if isNumber(val) {
+			got = strconv.FormatFloat(numberOf(val), 'f', -1, 64)
+		}
+		for _, opt := range strings.Fields(param) {
+			if got == opt {
+				return true
+			}
+		}
+		v.errorf(path, "value %s must be one of [%s]", got, param)
+		return false
+	case "gtfield":
+		if !parent.IsValid() {
+			return true
+		}
+		other := parent.FieldByName(param)
+		if isNumber(val) && isNumber(other) && numberOf(val) <= numberOf(other) {
+			v.errorf(path, "value %v must be greater than %s (%v)", val.Interface(), param, other.Interface())
+			return false
+		}
+		return true
+	case "gt", "gte", "lt", "lte":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return true
+		}
+		var got float64
+		what := "value"
+		switch {
+		case isNumber(val):
+			got = numberOf(val)
+		case val.Kind() == reflect.Slice || val.Kind() == reflect.Array || val.Kind() == reflect.Map || val.Kind() == reflect.String:
+			got = float64(val.Len())
+			what = "length"
+		default:
+			return true
+		}
+		var ok bool
+		var cond string
+		switch op {
+		case "gt":
+			ok, cond = got > limit, "greater than"
+		case "gte":
+			ok, cond = got >= limit, "greater than or equal to"
+		case "lt":
+			ok, cond = got < limit, "less than"
+		case "lte":
+			ok, cond = got <= limit, "less than or equal to"
+		}
+		if !ok {
+			v.errorf(path, "%s %v must be %s %s", what, strconv.FormatFloat(got, 'g', -1, 64), cond, param)
+		}
+		return ok
+	}
+	return true
+}
+
+func isEmptyValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return val.Len() == 0
+	case reflect.Invalid:
+		return true
+	}
+	return val.IsZero()
+}
+
+func isNumber(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func numberOf(val reflect.Value) float64 {
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint())
+	}
+	return val.Float()
+}
+
+func (v *validator) validateExtensionsRequired() {
+	for i, name := range v.doc.ExtensionsRequired {
+		if !v.doc.HasExtensionUsed(name) {
+			v.errorf(pointer("/extensionsRequired", i), "extension %s is required but not listed in extensionsUsed", name)
+		}
+	}
+}
+
+func (v *validator) validateScenes() {
+	doc := v.doc
+	v.checkOptionalIndex("/scene", doc.Scene, len(doc.Scenes), "scene")
+	isChild := make([]bool, len(doc.Nodes))
+	for _, n := range doc.Nodes {
+		for _, c := range n.Children {
+			if int(c) < len(isChild) {
+				isChild[c] = true
+			}
+		}
+	}
+	for i, s := range doc.Scenes {
+		for j, n := range s.Nodes {
+			path := pointer("/scenes", i, "nodes", j)
+			if v.checkIndex(path, n, len(doc.Nodes), "node") && isChild[n] {
+				v.errorf(path, "node %d is not a root node", n)
+			}
+		}
+	}
+}
+
+func (v *validator) validateNodes() {
+	doc := v.doc
+	parents := make([]int, len(doc.Nodes))
+	for i := range parents {
+		parents[i] = -1
+	}
+	for i, n := range doc.Nodes {
+		path := pointer("/nodes", i)
+		v.checkOptionalIndex(pointer(path, "camera"), n.Camera, len(doc.Cameras), "camera")
+		v.checkOptionalIndex(pointer(path, "skin"), n.Skin, len(doc.Skins), "skin")
+		if v.checkOptionalIndex(pointer(path, "mesh"), n.Mesh, len(doc.Meshes), "mesh") {
+			if targets := morphTargetCount(doc.Meshes[*n.Mesh]); len(n.Weights) > 0 && len(n.Weights) != targets {
+				v.errorf(pointer(path, "weights"), "length %d does not match the %d morph targets of mesh %d", len(n.Weights), targets, *n.Mesh)
+			}
+		} else if n.Skin != nil {
+			v.errorf(pointer(path, "skin"), "node with a skin must also define a mesh")
+		}
+		for j, c := range n.Children {
+			cpath := pointer(path, "children", j)
+			if !v.checkIndex(cpath, c, len(doc.Nodes), "node") {
+				continue
+			}
+			if int(c) == i {
+				v.errorf(cpath, "node %d is its own child", c)
+				continue
+			}
+			if p := parents[c]; p >= 0 && p != i {
+				v.errorf(cpath, "node %d has multiple parents (%d and %d)", c, p, i)
+				continue
+			}
+			parents[c] = i
+		}
+		hasTRS := n.Translation != DefaultTranslation ||
+			(n.Rotation != DefaultRotation && n.Rotation != emptyRotation) ||
+			(n.Scale != DefaultScale && n.Scale != emptyScale)
+		if n.Matrix != DefaultMatrix && n.Matrix != emptyMatrix && hasTRS {
+			v.errorf(pointer(path, "matrix"), "matrix must not be defined together with translation, rotation or scale")
+		}
+		if n.Rotation != emptyRotation {
+			q := n.Rotation
+			if l := q[0]*q[0] + q[1]*q[1] + q[2]*q[2] + q[3]*q[3]; l < 0.99 || l > 1.01 {
+				v.warnf(pointer(path, "rotation"), "quaternion is not normalized")
+			}
+		}
+	}
+	v.checkNodeCycles()
+}
+
+func (v *validator) checkNodeCycles() {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	nodes := v.doc.Nodes
+	state := make([]uint8, len(nodes))
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		state[i] = visiting
+		for j, c := range nodes[i].Children {
+			if int(c) >= len(nodes) || int(c) == i {
+				continue
+			}
+			switch state[c] {
+			case visiting:
+				v.errorf(pointer("/nodes", i, "children", j), "node hierarchy contains a cycle through node %d", c)
+				return false
+			case unvisited:
+				if !visit(int(c)) {
+					return false
+				}
+			}
+		}
+		state[i] = visited
+		return true
+	}
+	for i := range nodes {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+}
+
+func (v *validator) validateBuffers() {
+	for i, b := range v.doc.Buffers {
+		if len(b.Data) > 0 && uint32(len(b.Data)) < b.ByteLength {
+			v.errorf(pointer("/buffers", i, "byteLength"), "byteLength %d exceeds the %d bytes of loaded data", b.ByteLength, len(b.Data))
+		}
+	}
+}
+
+func (v *validator) validateBufferViews() {
+	doc := v.doc
+	for i, bv := range doc.BufferViews {
+		path := pointer("/bufferViews", i)
+		if !v.checkIndex(pointer(path, "buffer"), bv.Buffer, len(doc.Buffers), "buffer") {
+			continue
+		}
+		if end := uint64(bv.ByteOffset) + uint64(bv.ByteLength); end > uint64(doc.Buffers[bv.Buffer].ByteLength) {
+			v.errorf(pointer(path, "byteLength"), "range [%d, %d) exceeds buffer %d byteLength %d", bv.ByteOffset, end, bv.Buffer, doc.Buffers[bv.Buffer].ByteLength)
+		}
+		if bv.ByteStride%4 != 0 {
+			v.errorf(pointer(path, "byteStride"), "value %d must be a multiple of 4", bv.ByteStride)
+		}
+	}
+}
+
+func (v *validator) validateAccessors() {
+	doc := v.doc
+	for i, acr := range doc.Accessors {
+		path := pointer("/accessors", i)
+		components := int(acr.Type.Components())
+		if len(acr.Min) > 0 && len(acr.Min) != components {
+			v.errorf(pointer(path, "min"), "length %d does not match the %d components of %s", len(acr.Min), components, acr.Type)
+		}
+		if len(acr.Max) > 0 && len(acr.Max) != components {
+			v.errorf(pointer(path, "max"), "length %d does not match the %d components of %s", len(acr.Max), components, acr.Type)
+		}
+		elemSize := SizeOfElement(acr.ComponentType, acr.Type)
+		// An invalid componentType is reported by the tags, skip the alignment checks.
+		componentSize := acr.ComponentType.ByteSize()
+		if componentSize != 0 && acr.ByteOffset%componentSize != 0 {
+			v.errorf(pointer(path, "byteOffset"), "value %d must be a multiple of the component size %d", acr.ByteOffset, componentSize)
+		}
+		if acr.BufferView == nil {
+			if acr.ByteOffset != 0 {
+				v.errorf(pointer(path, "byteOffset"), "byteOffset must not be defined when bufferView is undefined")
+			}
+		} else if v.checkIndex(pointer(path, "bufferView"), *acr.BufferView, len(doc.BufferViews), "bufferView") {
+			bv := doc.BufferViews[*acr.BufferView]
+			stride := bv.ByteStride
+			if stride == 0 {
+				stride = elemSize
+			} else if stride < elemSize {
+				v.errorf(pointer(path, "bufferView"), "bufferView byteStride %d is smaller than the element size %d", stride, elemSize)
+			}
+			if acr.Count > 0 {
+				end := uint64(acr.ByteOffset) + uint64(stride)*uint64(acr.Count-1) + uint64(elemSize)
+				if end > uint64(bv.ByteLength) {
+					v.errorf(path, "accessor range [%d, %d) exceeds bufferView %d byteLength %d", acr.ByteOffset, end, *acr.BufferView, bv.ByteLength)
+				}
+			}
+			if componentSize != 0 && (bv.ByteOffset+acr.ByteOffset)%componentSize != 0 {
+				v.errorf(pointer(path, "byteOffset"), "accessor data is not aligned to the component size %d", componentSize)
+			}
+		}
+		if acr.Sparse != nil {
+			v.validateSparse(pointer(path, "sparse"), acr, elemSize)
+		}
+	}
+}
+
+func (v *validator) validateSparse(path string, acr *Accessor, elemSize uint32) {
+	doc := v.doc
+	sp := acr.Sparse
+	if sp.Count > acr.Count {
+		v.errorf(pointer(path, "count"), "value %d exceeds the accessor count %d", sp.Count, acr.Count)
+	}
+	check := func(path string, bufferView, byteOffset, size uint32) {
+		if !v.checkIndex(pointer(path, "bufferView"), bufferView, len(doc.BufferViews), "bufferView") {
+			return
+		}
+		bv := doc.BufferViews[bufferView]
+		if bv.ByteStride != 0 {
+			v.errorf(pointer(path, "bufferView"), "bufferView %d used by sparse storage must not define byteStride", bufferView)
+		}
+		if end := uint64(byteOffset) + uint64(size)*uint64(sp.Count); end > uint64(bv.ByteLength) {
+			v.errorf(path, "range [%d, %d) exceeds bufferView %d byteLength %d", byteOffset, end, bufferView, bv.ByteLength)
+		}
+	}
+	check(pointer(path, "indices"), sp.Indices.BufferView, sp.Indices.ByteOffset, sp.Indices.ComponentType.ByteSize())
+	check(pointer(path, "values"), sp.Values.BufferView, sp.Values.ByteOffset, elemSize)
+}
+
+func morphTargetCount(m *Mesh) int {
+	if len(m.Primitives) == 0 {
+		return 0
+	}
+	return len(m.Primitives[0].Targets)
+}
+
+func (v *validator) validateMeshes() {
+	doc := v.doc
+	for i, m := range doc.Meshes {
+		path := pointer("/meshes", i)
+		targets := morphTargetCount(m)
+		for j, p := range m.Primitives {
+			ppath := pointer(path, "primitives", j)
+			v.checkOptionalIndex(pointer(ppath, "material"), p.Material, len(doc.Materials), "material")
+			if v.checkOptionalIndex(pointer(ppath, "indices"), p.Indices, len(doc.Accessors), "accessor") {
+				acr := doc.Accessors[*p.Indices]
+				if acr.Type != AccessorScalar || (acr.ComponentType != ComponentUbyte && acr.ComponentType != ComponentUshort && acr.ComponentType != ComponentUint) {
+					v.errorf(pointer(ppath, "indices"), "accessor %d must be an unsigned integer SCALAR", *p.Indices)
+				}
+			}
+			v.validateAttributes(pointer(ppath, "attributes"), p.Attributes, true)
+			if len(p.Targets) != targets {
+				v.errorf(pointer(ppath, "targets"), "primitive has %d morph targets but the first primitive has %d", len(p.Targets), targets)
+			}
+			for k, t := range p.Targets {
+				v.validateAttributes(pointer(ppath, "targets", k), t, false)
+			}
+		}
+		if len(m.Weights) > 0 && len(m.Weights) != targets {
+			v.errorf(pointer(path, "weights"), "length %d does not match the %d morph targets", len(m.Weights), targets)
+		}
+	}
+}
+
+func (v *validator) validateAttributes(path string, attrs Attribute, requireBounds bool) {
+	doc := v.doc
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	count := -1
+	for _, name := range names {
+		index := attrs[name]
+		apath := pointer(path, name)
+		if !v.checkIndex(apath, index, len(doc.Accessors), "accessor") {
+			continue
+		}
+		acr := doc.Accessors[index]
+		if count == -1 {
+			count = int(acr.Count)
+		} else if int(acr.Count) != count {
+			v.errorf(apath, "accessor %d count %d differs from the other attributes count %d", index, acr.Count, count)
+		}
+		if requireBounds && name == POSITION && (len(acr.Min) == 0 || len(acr.Max) == 0) {
+			v.errorf(apath, "POSITION accessor %d must define min and max", index)
+		}
+	}
+}
+
+func (v *validator) validateMaterials() {
+	n := len(v.doc.Textures)
+	for i, m := range v.doc.Materials {
+		path := pointer("/materials", i)
+		if pbr := m.PBRMetallicRoughness; pbr != nil {
+			if pbr.BaseColorTexture != nil {
+				v.checkIndex(pointer(path, "pbrMetallicRoughness", "baseColorTexture", "index"), pbr.BaseColorTexture.Index, n, "texture")
+			}
+			if pbr.MetallicRoughnessTexture != nil {
+				v.checkIndex(pointer(path, "pbrMetallicRoughness", "metallicRoughnessTexture", "index"), pbr.MetallicRoughnessTexture.Index, n, "texture")
+			}
+		}
+		if m.NormalTexture != nil {
+			v.checkOptionalIndex(pointer(path, "normalTexture", "index"), m.NormalTexture.Index, n, "texture")
+		}
+		if m.OcclusionTexture != nil {
+			v.checkOptionalIndex(pointer(path, "occlusionTexture", "index"), m.OcclusionTexture.Index, n, "texture")
+		}
+		if m.EmissiveTexture != nil {
+			v.checkIndex(pointer(path, "emissiveTexture", "index"), m.EmissiveTexture.Index, n, "texture")
+		}
+	}
+}
+
+func (v *validator) validateTextures() {
+	doc := v.doc
+	for i, t := range doc.Textures {
+		path := pointer("/textures", i)
+		v.checkOptionalIndex(pointer(path, "sampler"), t.Sampler, len(doc.Samplers), "sampler")
+		v.checkOptionalIndex(pointer(path, "source"), t.Source, len(doc.Images), "image")
+	}
+}
+
+func (v *validator) validateImages() {
+	doc := v.doc
+	for i, im := range doc.Images {
+		path := pointer("/images", i)
+		if im.BufferView == nil {
+			continue
+		}
+		v.checkIndex(pointer(path, "bufferView"), *im.BufferView, len(doc.BufferViews), "bufferView")
+		if im.MimeType == "" {
+			v.errorf(pointer(path, "mimeType"), "mimeType is required when bufferView is defined")
+		}
+		if im.URI != "" {
+			v.errorf(pointer(path, "uri"), "uri must not be defined together with bufferView")
+		}
+	}
+}
+
+func (v *validator) validateCameras() {
+	for i, c := range v.doc.Cameras {
+		if (c.Perspective == nil) == (c.Orthographic == nil) {
+			v.errorf(pointer("/cameras", i), "camera must define exactly one of perspective or orthographic")
+		}
+		if p := c.Perspective; p != nil && p.Zfar != nil && *p.Zfar <= p.Znear {
+			v.errorf(pointer("/cameras", i, "perspective", "zfar"), "value %v must be greater than znear (%v)", *p.Zfar, p.Znear)
+		}
+	}
+}
+
+func (v *validator) validateSkins() {
+	doc := v.doc
+	for i, s := range doc.Skins {
+		path := pointer("/skins", i)
+		v.checkOptionalIndex(pointer(path, "skeleton"), s.Skeleton, len(doc.Nodes), "node")
+		for j, joint := range s.Joints {
+			v.checkIndex(pointer(path, "joints", j), joint, len(doc.Nodes), "node")
+		}
+		if v.checkOptionalIndex(pointer(path, "inverseBindMatrices"), s.InverseBindMatrices, len(doc.Accessors), "accessor") {
+			acr := doc.Accessors[*s.InverseBindMatrices]
+			if acr.Type != AccessorMat4 || acr.ComponentType != ComponentFloat {
+				v.errorf(pointer(path, "inverseBindMatrices"), "accessor %d must be a FLOAT MAT4", *s.InverseBindMatrices)
+			}
+			if int(acr.Count) < len(s.Joints) {
+				v.errorf(pointer(path, "inverseBindMatrices"), "accessor %d count %d is lower than the %d joints", *s.InverseBindMatrices, acr.Count, len(s.Joints))
+			}
+		}
+	}
+}
+
+func (v *validator) validateAnimations() {
+	doc := v.doc
+	for i, a := range doc.Animations {
+		path := pointer("/animations", i)
+		for j, s := range a.Samplers {
+			spath := pointer(path, "samplers", j)
+			if v.checkIndex(pointer(spath, "input"), s.Input, len(doc.Accessors), "accessor") {
+				acr := doc.Accessors[s.Input]
+				if acr.Type != AccessorScalar || acr.ComponentType != ComponentFloat {
+					v.errorf(pointer(spath, "input"), "accessor %d must be a FLOAT SCALAR", s.Input)
+				}
+				if len(acr.Min) == 0 || len(acr.Max) == 0 {
+					v.errorf(pointer(spath, "input"), "accessor %d must define min and max", s.Input)
+				}
+			}
+			v.checkIndex(pointer(spath, "output"), s.Output, len(doc.Accessors), "accessor")
+		}
+		type target struct {
+			node uint32
+			path TRSProperty
+		}
+		seen := make(map[target]struct{})
+		for j, c := range a.Channels {
+			cpath := pointer(path, "channels", j)
+			if c.Sampler == nil {
+				v.errorf(pointer(cpath, "sampler"), "value is required")
+			} else if v.checkIndex(pointer(cpath, "sampler"), *c.Sampler, len(a.Samplers), "sampler") {
+				v.validateChannelOutput(cpath, c, a.Samplers[*c.Sampler])
+			}
+			if c.Target.Node == nil {
+				continue
+			}
+			if !v.checkIndex(pointer(cpath, "target", "node"), *c.Target.Node, len(doc.Nodes), "node") {
+				continue
+			}
+			t := target{*c.Target.Node, c.Target.Path}
+			if _, ok := seen[t]; ok {
+				v.errorf(pointer(cpath, "target"), "node %d %s is already targeted by another channel", t.node, t.path)
+			}
+			seen[t] = struct{}{}
+			if c.Target.Path == TRSWeights {
+				n := doc.Nodes[*c.Target.Node]
+				if n.Mesh == nil || int(*n.Mesh) >= len(doc.Meshes) || morphTargetCount(doc.Meshes[*n.Mesh]) == 0 {
+					v.errorf(pointer(cpath, "target", "path"), "node %d has no mesh with morph targets", *c.Target.Node)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateChannelOutput(path string, c *Channel, s *AnimationSampler) {
+	doc := v.doc
+	if int(s.Input) >= len(doc.Accessors) || int(s.Output) >= len(doc.Accessors) {
+		return
+	}
+	input, output := doc.Accessors[s.Input], doc.Accessors[s.Output]
+	var want AccessorType
+	switch c.Target.Path {
+	case TRSTranslation, TRSScale:
+		want = AccessorVec3
+	case TRSRotation:
+		want = AccessorVec4
+	case TRSWeights:
+		want = AccessorScalar
+	}
+	if output.Type != want {
+		v.errorf(pointer(path, "target", "path"), "sampler output accessor %d must be %s but is %s", s.Output, want, output.Type)
+		return
+	}
+	expected := uint64(input.Count)
+	if s.Interpolation == InterpolationCubicSpline {
+		expected *= 3
+	}
+	if c.Target.Path == TRSWeights {
+		if c.Target.Node == nil || int(*c.Target.Node) >= len(doc.Nodes) {
+			return
+		}
+		n := doc.Nodes[*c.Target.Node]
+		if n.Mesh == nil || int(*n.Mesh) >= len(doc.Meshes) {
+			return
+		}
+		expected *= uint64(morphTargetCount(doc.Meshes[*n.Mesh]))
+	}
+	if uint64(output.Count) != expected {
+		v.errorf(pointer(path, "sampler"), "sampler output accessor %d count %d does not match the expected %d", s.Output, output.Count, expected)
+	}
+}
@@ -0,0 +1,125 @@
+package gltf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func paths(issues Issues, severity Severity) []string {
+	var ps []string
+	for _, is := range issues {
+		if is.Severity == severity {
+			ps = append(ps, is.Path)
+		}
+	}
+	return ps
+}
+
+func TestDocument_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(doc *Document)
+		want     []string
+		warnings []string
+	}{
+		{"valid", func(doc *Document) {}, nil, nil},
+		{"requiredTag", func(doc *Document) { doc.Accessors[1].Count = 0 }, []string{"/accessors/1/count"}, nil},
+		{"byteStrideTag", func(doc *Document) { doc.BufferViews[0].ByteStride = 256 }, []string{"/accessors/0", "/bufferViews/0/byteStride"}, nil},
+		{"samplerTag", func(doc *Document) { doc.Samplers[0].WrapS = 3 }, []string{"/samplers/0/wrapS"}, nil},
+		{"mimeTypeTag", func(doc *Document) { doc.Images[0].MimeType = "image/bmp" }, []string{"/images/0/mimeType"}, nil},
+		{"gifMimeType", func(doc *Document) { doc.Images[0].MimeType = "image/gif" }, nil, nil},
+		{"targetKeysTag", func(doc *Document) {
+			doc.Meshes[0].Primitives[0].Targets = []Attribute{{"COLOR_0": 0}}
+		}, []string{"/meshes/0/primitives/0/targets/0/COLOR_0"}, nil},
+		{"uniqueTag", func(doc *Document) { doc.Skins[0].Joints = []uint32{1, 1} }, []string{"/skins/0/inverseBindMatrices", "/skins/0/joints"}, nil},
+		{"orthographicTag", func(doc *Document) {
+			doc.Cameras = []*Camera{{Orthographic: &Orthographic{Zfar: 1, Znear: 2}}}
+		}, []string{"/cameras/0/orthographic/zfar"}, nil},
+		{"componentType", func(doc *Document) { doc.Accessors[4].ComponentType = 9 }, []string{"/accessors/4/componentType"}, nil},
+		{"accessorBounds", func(doc *Document) { doc.Accessors[0].Count = 4 }, []string{"/accessors/0"}, nil},
+		{"bufferViewBounds", func(doc *Document) { doc.BufferViews[4].ByteLength = 40 }, []string{"/bufferViews/4/byteLength"}, nil},
+		{"bufferIndex", func(doc *Document) { doc.BufferViews[0].Buffer = 1 }, []string{"/bufferViews/0/buffer"}, nil},
+		{"textureIndices", func(doc *Document) {
+			doc.Textures[0].Source = Index(1)
+			doc.Textures[0].Sampler = Index(2)
+		}, []string{"/textures/0/sampler", "/textures/0/source"}, nil},
+		{"materialTexture", func(doc *Document) {
+			doc.Materials[0].PBRMetallicRoughness.BaseColorTexture.Index = 5
+		}, []string{"/materials/0/pbrMetallicRoughness/baseColorTexture/index"}, nil},
+		{"nodeCycle", func(doc *Document) { doc.Nodes[1].Children = []uint32{0} }, []string{"/nodes/1/children/0", "/scenes/0/nodes/0"}, nil},
+		{"multipleParents", func(doc *Document) {
+			doc.Nodes = append(doc.Nodes, &Node{Children: []uint32{1}})
+		}, []string{"/nodes/2/children/0"}, nil},
+		{"skinJoint", func(doc *Document) { doc.Skins[0].Joints = []uint32{7} }, []string{"/skins/0/joints/0"}, nil},
+		{"skinMatrices", func(doc *Document) { doc.Skins[0].Joints = []uint32{0, 1} }, []string{"/skins/0/inverseBindMatrices"}, nil},
+		{"channelTarget", func(doc *Document) {
+			doc.Animations[0].Channels[0].Target.Node = Index(9)
+		}, []string{"/animations/0/channels/0/target/node"}, nil},
+		{"channelOutput", func(doc *Document) {
+			doc.Animations[0].Channels[0].Target.Path = TRSRotation
+		}, []string{"/animations/0/channels/0/target/path"}, nil},
+		{"channelWeights", func(doc *Document) {
+			doc.Animations[0].Channels[0].Target.Path = TRSWeights
+			doc.Animations[0].Samplers[0].Output = 3
+		}, []string{"/animations/0/channels/0/sampler", "/animations/0/channels/0/target/path"}, nil},
+		{"positionBounds", func(doc *Document) { doc.Accessors[0].Min = nil }, []string{"/meshes/0/primitives/0/attributes/POSITION"}, nil},
+		{"extensionsRequired", func(doc *Document) { doc.ExtensionsRequired = []string{"KHR_draco_mesh_compression"} }, []string{"/extensionsRequired/0"}, nil},
+		{"sparse", func(doc *Document) {
+			doc.Accessors[4].Sparse = &Sparse{Count: 3, Indices: SparseIndices{BufferView: 3, ComponentType: ComponentUint}, Values: SparseValues{BufferView: 9}}
+		}, []string{"/accessors/4/sparse/count", "/accessors/4/sparse/indices", "/accessors/4/sparse/values/bufferView"}, nil},
+		{"rotationWarning", func(doc *Document) { doc.Nodes[0].Rotation = [4]float32{0, 0, 0.5, 0.5} }, nil, []string{"/nodes/0/rotation"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				Asset:   Asset{Version: "2.0"},
+				Scene:   Index(0),
+				Scenes:  []*Scene{{Nodes: []uint32{0}}},
+				Nodes:   []*Node{{Mesh: Index(0), Children: []uint32{1}}, {Skin: Index(0), Mesh: Index(0)}},
+				Buffers: []*Buffer{{ByteLength: 152}},
+				BufferViews: []*BufferView{
+					{Buffer: 0, ByteLength: 36, Target: TargetArrayBuffer},
+					{Buffer: 0, ByteOffset: 36, ByteLength: 6, Target: TargetElementArrayBuffer},
+					{Buffer: 0, ByteOffset: 44, ByteLength: 64},
+					{Buffer: 0, ByteOffset: 108, ByteLength: 8},
+					{Buffer: 0, ByteOffset: 116, ByteLength: 24},
+				},
+				Accessors: []*Accessor{
+					{BufferView: Index(0), ComponentType: ComponentFloat, Type: AccessorVec3, Count: 3, Min: []float32{0, 0, 0}, Max: []float32{1, 1, 0}},
+					{BufferView: Index(1), ComponentType: ComponentUshort, Type: AccessorScalar, Count: 3},
+					{BufferView: Index(2), ComponentType: ComponentFloat, Type: AccessorMat4, Count: 1},
+					{BufferView: Index(3), ComponentType: ComponentFloat, Type: AccessorScalar, Count: 2, Min: []float32{0}, Max: []float32{1}},
+					{BufferView: Index(4), ComponentType: ComponentFloat, Type: AccessorVec3, Count: 2},
+				},
+				Meshes:    []*Mesh{{Primitives: []*Primitive{{Attributes: Attribute{POSITION: 0}, Indices: Index(1), Material: Index(0)}}}},
+				Materials: []*Material{{PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}}}},
+				Textures:  []*Texture{{Sampler: Index(0), Source: Index(0)}},
+				Samplers:  []*Sampler{{}},
+				Images:    []*Image{{URI: "a.png"}},
+				Skins:     []*Skin{{InverseBindMatrices: Index(2), Joints: []uint32{1}}},
+				Animations: []*Animation{{
+					Samplers: []*AnimationSampler{{Input: 3, Output: 4}},
+					Channels: []*Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(1), Path: TRSTranslation}}},
+				}},
+			}
+			tt.modify(doc)
+			got := doc.Validate()
+			if ps := paths(got, SeverityError); !reflect.DeepEqual(ps, tt.want) {
+				t.Errorf("Document.Validate() = %v, want %v", got, tt.want)
+			}
+			if (got.Err() != nil) != (len(tt.want) > 0) {
+				t.Errorf("Issues.Err() = %v", got.Err())
+			}
+			if ps := paths(got, SeverityWarning); !reflect.DeepEqual(ps, tt.warnings) {
+				t.Errorf("Document.Validate() warnings = %v, want %v", got, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestIssue_String(t *testing.T) {
+	is := Issue{Path: "/accessors/0/count", Severity: SeverityError, Message: "value is required"}
+	if got, want := is.String(), "error /accessors/0/count: value is required"; got != want {
+		t.Errorf("Issue.String() = %v, want %v", got, want)
+	}
+}